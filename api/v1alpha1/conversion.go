@@ -1,5 +1,5 @@
 /*
- * Copyright 2023 Hewlett Packard Enterprise Development LP
+ * Copyright 2023-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -21,6 +21,7 @@ package v1alpha1
 
 import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -41,12 +42,73 @@ func (src *LustreFileSystem) ConvertTo(dstRaw conversion.Hub) error {
 
 	// Manually restore data.
 	restored := &lusv1beta1.LustreFileSystem{}
-	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+	hasAnno, err := utilconversion.UnmarshalData(src, restored)
+	if err != nil {
 		return err
 	}
 	// EDIT THIS FUNCTION! If the annotation is holding anything that is
 	// hub-specific then copy it into 'dst' from 'restored'.
 	// Otherwise, you may comment out UnmarshalData() until it's needed.
+	if hasAnno {
+		dst.Spec.MgsNodes = restored.Spec.MgsNodes
+		dst.Spec.MgsNidsUpdatePolicy = restored.Spec.MgsNidsUpdatePolicy
+		dst.Spec.NamespaceSelector = restored.Spec.NamespaceSelector
+		dst.Spec.RevocationGracePeriodSeconds = restored.Spec.RevocationGracePeriodSeconds
+		dst.Spec.CSIDriver = restored.Spec.CSIDriver
+		dst.Spec.MountOptions = restored.Spec.MountOptions
+		dst.Spec.VolumeAttributes = restored.Spec.VolumeAttributes
+		dst.Spec.Subdirectory = restored.Spec.Subdirectory
+		dst.Spec.StorageClassPolicy = restored.Spec.StorageClassPolicy
+		dst.Spec.AccessPolicy = restored.Spec.AccessPolicy
+
+		for namespace, restoredNamespace := range restored.Spec.Namespaces {
+			dstNamespace, found := dst.Spec.Namespaces[namespace]
+			if !found {
+				continue
+			}
+
+			dstNamespace.MountOptions = restoredNamespace.MountOptions
+			dstNamespace.VolumeAttributes = restoredNamespace.VolumeAttributes
+			dstNamespace.Subdirectory = restoredNamespace.Subdirectory
+			dstNamespace.ClaimName = restoredNamespace.ClaimName
+			dstNamespace.Labels = restoredNamespace.Labels
+			dstNamespace.Annotations = restoredNamespace.Annotations
+			dstNamespace.ExpiresAt = restoredNamespace.ExpiresAt
+			dstNamespace.TTL = restoredNamespace.TTL
+			dstNamespace.Quota = restoredNamespace.Quota
+			dst.Spec.Namespaces[namespace] = dstNamespace
+		}
+		dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+		dst.Status.Conditions = restored.Status.Conditions
+		dst.Status.DeletionBlockedBy = restored.Status.DeletionBlockedBy
+
+		for namespace, restoredNamespace := range restored.Status.Namespaces {
+			dstNamespace, found := dst.Status.Namespaces[namespace]
+			if !found {
+				continue
+			}
+
+			dstNamespace.MatchedBySelector = restoredNamespace.MatchedBySelector
+			dstNamespace.ExportPath = restoredNamespace.ExportPath
+			dstNamespace.Rollover = restoredNamespace.Rollover
+			dstNamespace.GrantedAt = restoredNamespace.GrantedAt
+			dstNamespace.ExpiresAt = restoredNamespace.ExpiresAt
+			dstNamespace.Quota = restoredNamespace.Quota
+			dst.Status.Namespaces[namespace] = dstNamespace
+
+			for mode, restoredAccess := range restoredNamespace.Modes {
+				dstAccess, found := dstNamespace.Modes[mode]
+				if !found {
+					continue
+				}
+
+				dstAccess.Message = restoredAccess.Message
+				dstAccess.LastTransitionTime = restoredAccess.LastTransitionTime
+				dstAccess.Pods = restoredAccess.Pods
+				dstNamespace.Modes[mode] = dstAccess
+			}
+		}
+	}
 
 	return nil
 }
@@ -68,6 +130,29 @@ func (dst *LustreFileSystem) ConvertFrom(srcRaw conversion.Hub) error {
 // The conversion-gen tool generated the Convert_X_to_Y routines, should they
 // ever be needed.
 
+// The conversion-gen tool dropped these from zz_generated.conversion.go to
+// force us to acknowledge that we are addressing the conversion requirements.
+
+func Convert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(in *lusv1beta1.LustreFileSystemSpec, out *LustreFileSystemSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(in, out, s)
+}
+
+func Convert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(in *lusv1beta1.LustreFileSystemNamespaceSpec, out *LustreFileSystemNamespaceSpec, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(in, out, s)
+}
+
+func Convert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus(in *lusv1beta1.LustreFileSystemStatus, out *LustreFileSystemStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus(in, out, s)
+}
+
+func Convert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(in *lusv1beta1.LustreFileSystemNamespaceStatus, out *LustreFileSystemNamespaceStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(in, out, s)
+}
+
+func Convert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(in *lusv1beta1.LustreFileSystemNamespaceAccessStatus, out *LustreFileSystemNamespaceAccessStatus, s apiconversion.Scope) error {
+	return autoConvert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(in, out, s)
+}
+
 func resource(resource string) schema.GroupResource {
 	return schema.GroupResource{Group: "lus", Resource: resource}
 }
@@ -65,47 +65,47 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.LustreFileSystemNamespaceAccessStatus)(nil), (*LustreFileSystemNamespaceAccessStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(a.(*v1beta1.LustreFileSystemNamespaceAccessStatus), b.(*LustreFileSystemNamespaceAccessStatus), scope)
+	if err := s.AddGeneratedConversionFunc((*LustreFileSystemNamespaceSpec)(nil), (*v1beta1.LustreFileSystemNamespaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LustreFileSystemNamespaceSpec_To_v1beta1_LustreFileSystemNamespaceSpec(a.(*LustreFileSystemNamespaceSpec), b.(*v1beta1.LustreFileSystemNamespaceSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*LustreFileSystemNamespaceSpec)(nil), (*v1beta1.LustreFileSystemNamespaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_LustreFileSystemNamespaceSpec_To_v1beta1_LustreFileSystemNamespaceSpec(a.(*LustreFileSystemNamespaceSpec), b.(*v1beta1.LustreFileSystemNamespaceSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*LustreFileSystemNamespaceStatus)(nil), (*v1beta1.LustreFileSystemNamespaceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LustreFileSystemNamespaceStatus_To_v1beta1_LustreFileSystemNamespaceStatus(a.(*LustreFileSystemNamespaceStatus), b.(*v1beta1.LustreFileSystemNamespaceStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.LustreFileSystemNamespaceSpec)(nil), (*LustreFileSystemNamespaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(a.(*v1beta1.LustreFileSystemNamespaceSpec), b.(*LustreFileSystemNamespaceSpec), scope)
+	if err := s.AddGeneratedConversionFunc((*LustreFileSystemSpec)(nil), (*v1beta1.LustreFileSystemSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LustreFileSystemSpec_To_v1beta1_LustreFileSystemSpec(a.(*LustreFileSystemSpec), b.(*v1beta1.LustreFileSystemSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*LustreFileSystemNamespaceStatus)(nil), (*v1beta1.LustreFileSystemNamespaceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_LustreFileSystemNamespaceStatus_To_v1beta1_LustreFileSystemNamespaceStatus(a.(*LustreFileSystemNamespaceStatus), b.(*v1beta1.LustreFileSystemNamespaceStatus), scope)
+	if err := s.AddGeneratedConversionFunc((*LustreFileSystemStatus)(nil), (*v1beta1.LustreFileSystemStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_LustreFileSystemStatus_To_v1beta1_LustreFileSystemStatus(a.(*LustreFileSystemStatus), b.(*v1beta1.LustreFileSystemStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.LustreFileSystemNamespaceStatus)(nil), (*LustreFileSystemNamespaceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(a.(*v1beta1.LustreFileSystemNamespaceStatus), b.(*LustreFileSystemNamespaceStatus), scope)
+	if err := s.AddConversionFunc((*v1beta1.LustreFileSystemNamespaceAccessStatus)(nil), (*LustreFileSystemNamespaceAccessStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(a.(*v1beta1.LustreFileSystemNamespaceAccessStatus), b.(*LustreFileSystemNamespaceAccessStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*LustreFileSystemSpec)(nil), (*v1beta1.LustreFileSystemSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_LustreFileSystemSpec_To_v1beta1_LustreFileSystemSpec(a.(*LustreFileSystemSpec), b.(*v1beta1.LustreFileSystemSpec), scope)
+	if err := s.AddConversionFunc((*v1beta1.LustreFileSystemNamespaceSpec)(nil), (*LustreFileSystemNamespaceSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(a.(*v1beta1.LustreFileSystemNamespaceSpec), b.(*LustreFileSystemNamespaceSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.LustreFileSystemSpec)(nil), (*LustreFileSystemSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(a.(*v1beta1.LustreFileSystemSpec), b.(*LustreFileSystemSpec), scope)
+	if err := s.AddConversionFunc((*v1beta1.LustreFileSystemNamespaceStatus)(nil), (*LustreFileSystemNamespaceStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(a.(*v1beta1.LustreFileSystemNamespaceStatus), b.(*LustreFileSystemNamespaceStatus), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*LustreFileSystemStatus)(nil), (*v1beta1.LustreFileSystemStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1alpha1_LustreFileSystemStatus_To_v1beta1_LustreFileSystemStatus(a.(*LustreFileSystemStatus), b.(*v1beta1.LustreFileSystemStatus), scope)
+	if err := s.AddConversionFunc((*v1beta1.LustreFileSystemSpec)(nil), (*LustreFileSystemSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(a.(*v1beta1.LustreFileSystemSpec), b.(*LustreFileSystemSpec), scope)
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta1.LustreFileSystemStatus)(nil), (*LustreFileSystemStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+	if err := s.AddConversionFunc((*v1beta1.LustreFileSystemStatus)(nil), (*LustreFileSystemStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus(a.(*v1beta1.LustreFileSystemStatus), b.(*LustreFileSystemStatus), scope)
 	}); err != nil {
 		return err
@@ -147,7 +147,17 @@ func Convert_v1beta1_LustreFileSystem_To_v1alpha1_LustreFileSystem(in *v1beta1.L
 
 func autoConvert_v1alpha1_LustreFileSystemList_To_v1beta1_LustreFileSystemList(in *LustreFileSystemList, out *v1beta1.LustreFileSystemList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta1.LustreFileSystem)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta1.LustreFileSystem, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha1_LustreFileSystem_To_v1beta1_LustreFileSystem(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -158,7 +168,17 @@ func Convert_v1alpha1_LustreFileSystemList_To_v1beta1_LustreFileSystemList(in *L
 
 func autoConvert_v1beta1_LustreFileSystemList_To_v1alpha1_LustreFileSystemList(in *v1beta1.LustreFileSystemList, out *LustreFileSystemList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]LustreFileSystem)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LustreFileSystem, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta1_LustreFileSystem_To_v1alpha1_LustreFileSystem(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -183,14 +203,12 @@ func autoConvert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_Lustr
 	out.State = NamespaceAccessState(in.State)
 	out.PersistentVolumeRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.PersistentVolumeRef))
 	out.PersistentVolumeClaimRef = (*v1.LocalObjectReference)(unsafe.Pointer(in.PersistentVolumeClaimRef))
+	// WARNING: in.Message requires manual conversion: does not exist in peer-type
+	// WARNING: in.LastTransitionTime requires manual conversion: does not exist in peer-type
+	// WARNING: in.Pods requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus is an autogenerated conversion function.
-func Convert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(in *v1beta1.LustreFileSystemNamespaceAccessStatus, out *LustreFileSystemNamespaceAccessStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(in, out, s)
-}
-
 func autoConvert_v1alpha1_LustreFileSystemNamespaceSpec_To_v1beta1_LustreFileSystemNamespaceSpec(in *LustreFileSystemNamespaceSpec, out *v1beta1.LustreFileSystemNamespaceSpec, s conversion.Scope) error {
 	out.Modes = *(*[]v1.PersistentVolumeAccessMode)(unsafe.Pointer(&in.Modes))
 	return nil
@@ -203,16 +221,32 @@ func Convert_v1alpha1_LustreFileSystemNamespaceSpec_To_v1beta1_LustreFileSystemN
 
 func autoConvert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(in *v1beta1.LustreFileSystemNamespaceSpec, out *LustreFileSystemNamespaceSpec, s conversion.Scope) error {
 	out.Modes = *(*[]v1.PersistentVolumeAccessMode)(unsafe.Pointer(&in.Modes))
+	// WARNING: in.Subdirectory requires manual conversion: does not exist in peer-type
+	// WARNING: in.MountOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeAttributes requires manual conversion: does not exist in peer-type
+	// WARNING: in.ClaimName requires manual conversion: does not exist in peer-type
+	// WARNING: in.Labels requires manual conversion: does not exist in peer-type
+	// WARNING: in.Annotations requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpiresAt requires manual conversion: does not exist in peer-type
+	// WARNING: in.TTL requires manual conversion: does not exist in peer-type
+	// WARNING: in.Quota requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec is an autogenerated conversion function.
-func Convert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(in *v1beta1.LustreFileSystemNamespaceSpec, out *LustreFileSystemNamespaceSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(in, out, s)
-}
-
 func autoConvert_v1alpha1_LustreFileSystemNamespaceStatus_To_v1beta1_LustreFileSystemNamespaceStatus(in *LustreFileSystemNamespaceStatus, out *v1beta1.LustreFileSystemNamespaceStatus, s conversion.Scope) error {
-	out.Modes = *(*map[v1.PersistentVolumeAccessMode]v1beta1.LustreFileSystemNamespaceAccessStatus)(unsafe.Pointer(&in.Modes))
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make(map[v1.PersistentVolumeAccessMode]v1beta1.LustreFileSystemNamespaceAccessStatus, len(*in))
+		for key, val := range *in {
+			newVal := new(v1beta1.LustreFileSystemNamespaceAccessStatus)
+			if err := Convert_v1alpha1_LustreFileSystemNamespaceAccessStatus_To_v1beta1_LustreFileSystemNamespaceAccessStatus(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Modes = nil
+	}
 	return nil
 }
 
@@ -222,21 +256,46 @@ func Convert_v1alpha1_LustreFileSystemNamespaceStatus_To_v1beta1_LustreFileSyste
 }
 
 func autoConvert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(in *v1beta1.LustreFileSystemNamespaceStatus, out *LustreFileSystemNamespaceStatus, s conversion.Scope) error {
-	out.Modes = *(*map[v1.PersistentVolumeAccessMode]LustreFileSystemNamespaceAccessStatus)(unsafe.Pointer(&in.Modes))
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make(map[v1.PersistentVolumeAccessMode]LustreFileSystemNamespaceAccessStatus, len(*in))
+		for key, val := range *in {
+			newVal := new(LustreFileSystemNamespaceAccessStatus)
+			if err := Convert_v1beta1_LustreFileSystemNamespaceAccessStatus_To_v1alpha1_LustreFileSystemNamespaceAccessStatus(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Modes = nil
+	}
+	// WARNING: in.ExportPath requires manual conversion: does not exist in peer-type
+	// WARNING: in.MatchedBySelector requires manual conversion: does not exist in peer-type
+	// WARNING: in.Rollover requires manual conversion: does not exist in peer-type
+	// WARNING: in.GrantedAt requires manual conversion: does not exist in peer-type
+	// WARNING: in.ExpiresAt requires manual conversion: does not exist in peer-type
+	// WARNING: in.Quota requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus is an autogenerated conversion function.
-func Convert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(in *v1beta1.LustreFileSystemNamespaceStatus, out *LustreFileSystemNamespaceStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(in, out, s)
-}
-
 func autoConvert_v1alpha1_LustreFileSystemSpec_To_v1beta1_LustreFileSystemSpec(in *LustreFileSystemSpec, out *v1beta1.LustreFileSystemSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.MgsNids = in.MgsNids
 	out.MountRoot = in.MountRoot
 	out.StorageClassName = in.StorageClassName
-	out.Namespaces = *(*map[string]v1beta1.LustreFileSystemNamespaceSpec)(unsafe.Pointer(&in.Namespaces))
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]v1beta1.LustreFileSystemNamespaceSpec, len(*in))
+		for key, val := range *in {
+			newVal := new(v1beta1.LustreFileSystemNamespaceSpec)
+			if err := Convert_v1alpha1_LustreFileSystemNamespaceSpec_To_v1beta1_LustreFileSystemNamespaceSpec(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Namespaces = nil
+	}
 	return nil
 }
 
@@ -248,19 +307,48 @@ func Convert_v1alpha1_LustreFileSystemSpec_To_v1beta1_LustreFileSystemSpec(in *L
 func autoConvert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(in *v1beta1.LustreFileSystemSpec, out *LustreFileSystemSpec, s conversion.Scope) error {
 	out.Name = in.Name
 	out.MgsNids = in.MgsNids
+	// WARNING: in.MgsNodes requires manual conversion: does not exist in peer-type
+	// WARNING: in.MgsNidsUpdatePolicy requires manual conversion: does not exist in peer-type
 	out.MountRoot = in.MountRoot
+	// WARNING: in.CSIDriver requires manual conversion: does not exist in peer-type
+	// WARNING: in.MountOptions requires manual conversion: does not exist in peer-type
+	// WARNING: in.VolumeAttributes requires manual conversion: does not exist in peer-type
+	// WARNING: in.Subdirectory requires manual conversion: does not exist in peer-type
 	out.StorageClassName = in.StorageClassName
-	out.Namespaces = *(*map[string]LustreFileSystemNamespaceSpec)(unsafe.Pointer(&in.Namespaces))
+	// WARNING: in.StorageClassPolicy requires manual conversion: does not exist in peer-type
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]LustreFileSystemNamespaceSpec, len(*in))
+		for key, val := range *in {
+			newVal := new(LustreFileSystemNamespaceSpec)
+			if err := Convert_v1beta1_LustreFileSystemNamespaceSpec_To_v1alpha1_LustreFileSystemNamespaceSpec(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Namespaces = nil
+	}
+	// WARNING: in.NamespaceSelector requires manual conversion: does not exist in peer-type
+	// WARNING: in.RevocationGracePeriodSeconds requires manual conversion: does not exist in peer-type
+	// WARNING: in.AccessPolicy requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec is an autogenerated conversion function.
-func Convert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(in *v1beta1.LustreFileSystemSpec, out *LustreFileSystemSpec, s conversion.Scope) error {
-	return autoConvert_v1beta1_LustreFileSystemSpec_To_v1alpha1_LustreFileSystemSpec(in, out, s)
-}
-
 func autoConvert_v1alpha1_LustreFileSystemStatus_To_v1beta1_LustreFileSystemStatus(in *LustreFileSystemStatus, out *v1beta1.LustreFileSystemStatus, s conversion.Scope) error {
-	out.Namespaces = *(*map[string]v1beta1.LustreFileSystemNamespaceStatus)(unsafe.Pointer(&in.Namespaces))
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]v1beta1.LustreFileSystemNamespaceStatus, len(*in))
+		for key, val := range *in {
+			newVal := new(v1beta1.LustreFileSystemNamespaceStatus)
+			if err := Convert_v1alpha1_LustreFileSystemNamespaceStatus_To_v1beta1_LustreFileSystemNamespaceStatus(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Namespaces = nil
+	}
 	return nil
 }
 
@@ -270,11 +358,21 @@ func Convert_v1alpha1_LustreFileSystemStatus_To_v1beta1_LustreFileSystemStatus(i
 }
 
 func autoConvert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus(in *v1beta1.LustreFileSystemStatus, out *LustreFileSystemStatus, s conversion.Scope) error {
-	out.Namespaces = *(*map[string]LustreFileSystemNamespaceStatus)(unsafe.Pointer(&in.Namespaces))
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make(map[string]LustreFileSystemNamespaceStatus, len(*in))
+		for key, val := range *in {
+			newVal := new(LustreFileSystemNamespaceStatus)
+			if err := Convert_v1beta1_LustreFileSystemNamespaceStatus_To_v1alpha1_LustreFileSystemNamespaceStatus(&val, newVal, s); err != nil {
+				return err
+			}
+			(*out)[key] = *newVal
+		}
+	} else {
+		out.Namespaces = nil
+	}
+	// WARNING: in.ObservedGeneration requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	// WARNING: in.DeletionBlockedBy requires manual conversion: does not exist in peer-type
 	return nil
 }
-
-// Convert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus is an autogenerated conversion function.
-func Convert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus(in *v1beta1.LustreFileSystemStatus, out *LustreFileSystemStatus, s conversion.Scope) error {
-	return autoConvert_v1beta1_LustreFileSystemStatus_To_v1alpha1_LustreFileSystemStatus(in, out, s)
-}
@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2023 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -20,11 +20,21 @@
 package v1beta1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/DataWorkflowServices/dws/utils/updater"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -45,16 +55,205 @@ type LustreFileSystemSpec struct {
 	// nodes to use for accessing the Lustre file system.
 	MgsNids string `json:"mgsNids"`
 
+	// MgsNodes is the structured form of MgsNids. It lists the MGS nodes in failover order, with the
+	// primary MGS node first. Either MgsNids or MgsNodes may be specified and the other is filled in
+	// to match; when both are specified they must describe the same NIDs.
+	// +optional
+	MgsNodes []LustreFileSystemMgsNode `json:"mgsNodes,omitempty"`
+
+	// MgsNidsUpdatePolicy controls whether the MGS NIDs may be changed. With 'Rollover', a persistent volume
+	// with the updated NIDs is created alongside each existing persistent volume, and each claim is moved
+	// to its new persistent volume once no pod is using it. When MgsNodes is set, it must be updated too.
+	// +kubebuilder:default:="Forbid"
+	// +optional
+	MgsNidsUpdatePolicy MgsNidsUpdatePolicy `json:"mgsNidsUpdatePolicy,omitempty"`
+
 	// MountRoot is the mount path used to access the Lustre file system from a host. Data Movement
 	// directives and Container Profiles can reference this field.
 	MountRoot string `json:"mountRoot"`
 
-	// StorageClassName refers to the StorageClass to use for this file system.
+	// CSIDriver is the name of the CSI driver used to mount the Lustre file system. When empty, the
+	// operator's default CSI driver is used.
+	// +optional
+	CSIDriver string `json:"csiDriver,omitempty"`
+
+	// MountOptions are the mount options used by clients mounting the Lustre file system, such as
+	// 'flock' or 'noatime'. A namespace may override them.
+	// +optional
+	MountOptions []string `json:"mountOptions,omitempty"`
+
+	// VolumeAttributes are passed to the CSI driver in the persistent volume. A namespace may override
+	// individual attributes.
+	// +optional
+	VolumeAttributes map[string]string `json:"volumeAttributes,omitempty"`
+
+	// Subdirectory is the default subdirectory of the file system exported to each namespace. It is a
+	// template that may reference {{.Namespace}}, {{.Name}} (the LustreFileSystem name) and {{.FileSystemName}}.
+	// When empty, the root of the file system is exported. The subdirectory must exist in the file system.
+	// +optional
+	Subdirectory string `json:"subdirectory,omitempty"`
+
+	// StorageClassName refers to the StorageClass to use for this file system. The operator's default
+	// storage class, when it has one, replaces the default of 'nnf-lustre-fs' when the file system is created.
 	// +kubebuilder:default:="nnf-lustre-fs"
 	StorageClassName string `json:"storageClassName,omitempty"`
 
+	// StorageClassPolicy controls whether the operator creates the storage class. With 'Managed', the storage
+	// class is created for this file system and deleted with it, so no other file system may use it. With
+	// 'Existing', the storage class must already exist. Either way, the storage class must use the CSI driver
+	// of the file system, or no provisioner, and bind volumes immediately.
+	// +kubebuilder:default:="Existing"
+	// +optional
+	StorageClassPolicy StorageClassPolicy `json:"storageClassPolicy,omitempty"`
+
 	// Namespaces defines a map of namespaces with access to the Lustre file systems
 	Namespaces map[string]LustreFileSystemNamespaceSpec `json:"namespaces,omitempty"`
+
+	// NamespaceSelector grants access to every namespace with labels matching the selector. A namespace
+	// listed in Namespaces uses the modes listed there instead of the modes of the selector.
+	// +optional
+	NamespaceSelector *LustreFileSystemNamespaceSelector `json:"namespaceSelector,omitempty"`
+
+	// RevocationGracePeriodSeconds is how long a revoked namespace access waits for the pods using its persistent
+	// volume claim to finish before the claim and persistent volume are deleted. When empty, the operator's default
+	// grace period is used. Zero deletes them immediately.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	RevocationGracePeriodSeconds *int64 `json:"revocationGracePeriodSeconds,omitempty"`
+
+	// AccessPolicy allows namespaces to request access to the file system by creating a LustreFileSystemAccess.
+	// Requests are denied when there is no access policy.
+	// +optional
+	AccessPolicy *LustreFileSystemAccessPolicy `json:"accessPolicy,omitempty"`
+}
+
+// LustreFileSystemAccessPolicy describes the access a namespace may request through a LustreFileSystemAccess
+type LustreFileSystemAccessPolicy struct {
+	// Namespaces lists the namespaces allowed to request access
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// NamespaceSelector selects the namespaces allowed to request access by label
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Modes lists the access modes that may be requested. Every supported mode may be requested when the list
+	// is empty.
+	// +optional
+	Modes []corev1.PersistentVolumeAccessMode `json:"modes,omitempty"`
+
+	// Approval controls whether an allowed request is granted right away. With 'Manual', an administrator
+	// approves the request by setting the Approved condition in its status to true; see LustreFileSystemAccess.
+	// +kubebuilder:default:="Manual"
+	// +optional
+	Approval AccessApproval `json:"approval,omitempty"`
+}
+
+// AccessApproval describes how an allowed access request is approved
+// +kubebuilder:validation:Enum:=Automatic;Manual
+type AccessApproval string
+
+const (
+	// AccessApprovalAutomatic - used to grant an allowed access request without waiting for an administrator
+	AccessApprovalAutomatic AccessApproval = "Automatic"
+
+	// AccessApprovalManual - used to wait for an administrator to approve an allowed access request
+	AccessApprovalManual AccessApproval = "Manual"
+)
+
+// MgsNidsUpdatePolicy describes how a change to the MGS NIDs is handled
+// +kubebuilder:validation:Enum:=Forbid;Rollover
+type MgsNidsUpdatePolicy string
+
+const (
+	// MgsNidsUpdateForbid - used to forbid any change to the MGS NIDs
+	MgsNidsUpdateForbid MgsNidsUpdatePolicy = "Forbid"
+
+	// MgsNidsUpdateRollover - used to allow the MGS NIDs to change, moving each claim to a new persistent volume
+	MgsNidsUpdateRollover MgsNidsUpdatePolicy = "Rollover"
+)
+
+// StorageClassPolicy describes how the storage class of a file system is provided
+// +kubebuilder:validation:Enum:=Existing;Managed
+type StorageClassPolicy string
+
+const (
+	// StorageClassExisting - used when the storage class is created outside the operator
+	StorageClassExisting StorageClassPolicy = "Existing"
+
+	// StorageClassManaged - used when the operator creates the storage class and deletes it with the file system
+	StorageClassManaged StorageClassPolicy = "Managed"
+)
+
+// LustreFileSystemMgsNode defines the NIDs of one MGS node
+type LustreFileSystemMgsNode struct {
+	// Nids are the NIDs of the MGS node, one for each LNet network the node is reachable on.
+	// +kubebuilder:validation:MinItems:=1
+	Nids []string `json:"nids"`
+}
+
+// LustreFileSystemNamespaceSelector selects the namespaces with access to the Lustre file system by label
+type LustreFileSystemNamespaceSelector struct {
+	metav1.LabelSelector `json:",inline"`
+
+	// Modes list the persistent volume access modes for the namespaces matching the selector. A ReadOnlyMany
+	// access is mounted read only. ReadWriteOncePod is not supported.
+	Modes []corev1.PersistentVolumeAccessMode `json:"modes,omitempty"`
+
+	// ClaimName replaces the generated name of the persistent volume claims in the namespaces matching the
+	// selector. See LustreFileSystemNamespaceSpec.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Labels are added to the persistent volumes and claims of the namespaces matching the selector. See
+	// LustreFileSystemNamespaceSpec.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the persistent volumes and claims of the namespaces matching the selector. See
+	// LustreFileSystemNamespaceSpec.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// AccessAllowed returns nil when the access policy allows the namespace to request the mode, or an error
+// describing why the request is not allowed
+func (fs *LustreFileSystem) AccessAllowed(ns *corev1.Namespace, mode corev1.PersistentVolumeAccessMode) error {
+	policy := fs.Spec.AccessPolicy
+	if policy == nil {
+		return fmt.Errorf("file system '%s/%s' does not accept access requests", fs.Namespace, fs.Name)
+	}
+
+	if len(policy.Modes) != 0 && !slices.Contains(policy.Modes, mode) {
+		return fmt.Errorf("mode %s is not allowed by file system '%s/%s'", mode, fs.Namespace, fs.Name)
+	}
+
+	if slices.Contains(policy.Namespaces, ns.Name) {
+		return nil
+	}
+
+	if policy.NamespaceSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(policy.NamespaceSelector)
+		if err != nil {
+			return err
+		}
+
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("namespace '%s' is not allowed to request access to file system '%s/%s'", ns.Name, fs.Namespace, fs.Name)
+}
+
+// NamespaceSpec returns the namespace specification of the namespaces matching the selector
+func (s *LustreFileSystemNamespaceSelector) NamespaceSpec() LustreFileSystemNamespaceSpec {
+	return LustreFileSystemNamespaceSpec{
+		Modes:       s.Modes,
+		ClaimName:   s.ClaimName,
+		Labels:      s.Labels,
+		Annotations: s.Annotations,
+	}
 }
 
 // LustreFileSystemAccessSpec defines the desired state of Lustre File System Accesses
@@ -62,8 +261,84 @@ type LustreFileSystemNamespaceSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// Modes list the persistent volume access modes for accessing the Lustre file system.
+	// Modes list the persistent volume access modes for accessing the Lustre file system. A ReadOnlyMany
+	// access is mounted read only. ReadWriteOncePod is not supported. The operator's default modes are
+	// used when the list is empty.
 	Modes []corev1.PersistentVolumeAccessMode `json:"modes,omitempty"`
+
+	// Subdirectory replaces the default subdirectory of the file system exported to this namespace. It is
+	// a template with the same fields as the default.
+	// +optional
+	Subdirectory string `json:"subdirectory,omitempty"`
+
+	// MountOptions replace the mount options of the file system for this namespace.
+	// +optional
+	MountOptions []string `json:"mountOptions,omitempty"`
+
+	// VolumeAttributes are merged with the volume attributes of the file system for this namespace,
+	// replacing any attribute with the same key.
+	// +optional
+	VolumeAttributes map[string]string `json:"volumeAttributes,omitempty"`
+
+	// ClaimName replaces the generated name of the persistent volume claims of this namespace, so pods can
+	// refer to a stable name such as 'home'. It is a template with the same fields as the subdirectory along
+	// with {{.Mode}}, the lowercase access mode, which it must use when more than one mode is listed. The claim
+	// name can't change while the namespace has access.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+
+	// Labels are added to the persistent volumes and claims of this namespace. The values are templates with
+	// the same fields as the claim name.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations are added to the persistent volumes and claims of this namespace. The values are templates
+	// with the same fields as the claim name.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// ExpiresAt is the time the namespace access expires. The access is then revoked as if the namespace were
+	// removed from the specification, and its status records the expiration. Only one of ExpiresAt and TTL
+	// may be set.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// TTL is how long the namespace access lasts, counted from the time the namespace was first granted access
+	// as recorded in its status. Only one of ExpiresAt and TTL may be set.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+
+	// Quota limits the space and the number of files used in the subdirectory exported to this namespace with
+	// a Lustre project quota. The namespace must have a subdirectory. The operator allocates the project ID and
+	// records it in the namespace status.
+	// +optional
+	Quota *LustreFileSystemNamespaceQuota `json:"quota,omitempty"`
+}
+
+// LustreFileSystemNamespaceQuota defines the hard limits of the project quota of a namespace
+type LustreFileSystemNamespaceQuota struct {
+	// BlockLimit is the limit on the space used by the namespace. There is no limit on the space when empty.
+	// +optional
+	BlockLimit *resource.Quantity `json:"blockLimit,omitempty"`
+
+	// InodeLimit is the limit on the number of files and directories of the namespace. There is no limit on
+	// the number of files when empty.
+	// +kubebuilder:validation:Minimum:=0
+	// +optional
+	InodeLimit *int64 `json:"inodeLimit,omitempty"`
+}
+
+// Expiry returns the time the namespace access granted at the given time expires, or nil when it doesn't expire
+func (s *LustreFileSystemNamespaceSpec) Expiry(grantedAt metav1.Time) *metav1.Time {
+	switch {
+	case s.ExpiresAt != nil:
+		return s.ExpiresAt.DeepCopy()
+	case s.TTL != nil:
+		expiresAt := metav1.NewTime(grantedAt.Add(s.TTL.Duration))
+		return &expiresAt
+	}
+
+	return nil
 }
 
 // LustreFileSystemStatus defines the observed status of LustreFileSystem
@@ -73,6 +348,18 @@ type LustreFileSystemStatus struct {
 
 	// Namespaces contains the namespaces supported for this Lustre file system and their corresponding status.
 	Namespaces map[string]LustreFileSystemNamespaceStatus `json:"namespaces,omitempty"`
+
+	// ObservedGeneration is the generation of the specification that was last reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the file system's state.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DeletionBlockedBy lists the pods using one of the file system's persistent volume claims. Deletion of
+	// the file system waits for them to finish unless the force delete annotation is set.
+	DeletionBlockedBy []string `json:"deletionBlockedBy,omitempty"`
 }
 
 // LustreFileSystemAccessStatus defines the observe status of access to the LustreFileSystem
@@ -82,6 +369,65 @@ type LustreFileSystemNamespaceStatus struct {
 
 	// Modes contains the modes supported for this namespace and their corresponding access sttatus.
 	Modes map[corev1.PersistentVolumeAccessMode]LustreFileSystemNamespaceAccessStatus `json:"modes,omitempty"`
+
+	// ExportPath is the Lustre path, including the MGS NIDs, exported to the namespace.
+	ExportPath string `json:"exportPath,omitempty"`
+
+	// MatchedBySelector is true when the namespace has access because it matches the namespace selector
+	// rather than being listed in the specification.
+	MatchedBySelector bool `json:"matchedBySelector,omitempty"`
+
+	// Rollover reports the progress of moving the namespace's claims to persistent volumes with the
+	// current export path. It is empty when no rollover is in progress.
+	Rollover *LustreFileSystemNamespaceRolloverStatus `json:"rollover,omitempty"`
+
+	// GrantedAt is the time the namespace was first granted access. The TTL of the access counts from it.
+	GrantedAt *metav1.Time `json:"grantedAt,omitempty"`
+
+	// ExpiresAt is the time the namespace access expires, if it does.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Quota reports the project quota of the namespace, if it has one.
+	Quota *LustreFileSystemNamespaceQuotaStatus `json:"quota,omitempty"`
+}
+
+// LustreFileSystemNamespaceQuotaStatus defines the observed status of the project quota of a namespace
+type LustreFileSystemNamespaceQuotaStatus struct {
+	// ProjectID is the Lustre project ID allocated to the namespace. It's kept while the namespace has a quota.
+	ProjectID int64 `json:"projectID,omitempty"`
+
+	// Path is the directory, as mounted at the mount root, the project ID was assigned to
+	Path string `json:"path,omitempty"`
+
+	// Limits are the limits last set on the project
+	Limits *LustreFileSystemNamespaceQuota `json:"limits,omitempty"`
+
+	// BlockUsage is the space used by the namespace when the quota was last updated
+	BlockUsage *resource.Quantity `json:"blockUsage,omitempty"`
+
+	// InodeUsage is the number of files and directories of the namespace when the quota was last updated
+	InodeUsage int64 `json:"inodeUsage,omitempty"`
+
+	// LastUpdateTime is the last time the usage was read
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// Message describes why the quota could not be set or read, if it couldn't
+	Message string `json:"message,omitempty"`
+}
+
+// LustreFileSystemNamespaceRolloverStatus defines the observed status of a rollover of the namespace's claims
+type LustreFileSystemNamespaceRolloverStatus struct {
+	// ExportPath is the Lustre path of the persistent volumes the claims are moving to.
+	ExportPath string `json:"exportPath,omitempty"`
+
+	// PendingModes lists the access modes whose claims are still bound to a previous persistent volume.
+	PendingModes []corev1.PersistentVolumeAccessMode `json:"pendingModes,omitempty"`
+
+	// Pods lists the pods using a claim that is waiting to move. The claim moves once they finish.
+	Pods []string `json:"pods,omitempty"`
+
+	// StartTime is the time the rollover started
+	StartTime *metav1.Time `json:"startTime,omitempty"`
 }
 
 // LustreFileSystemNamespaceAccessStatus defines the observe status of namespace access to the LustreFileSystem
@@ -92,11 +438,21 @@ type LustreFileSystemNamespaceAccessStatus struct {
 	// State represents the current state of the namespace access
 	State NamespaceAccessState `json:"state"`
 
-	// PersistentVolumeRef holds a reference to the persistent volume, if present
+	// PersistentVolumeRef holds a reference to the persistent volume, if present. A claim keeps the persistent
+	// volume it is bound to, so the name may follow an earlier naming scheme.
 	PersistentVolumeRef *corev1.LocalObjectReference `json:"persistentVolumeRef,omitempty"`
 
 	// PersistentVolumeClaimRef holds a reference to the persistent volume claim, if present
 	PersistentVolumeClaimRef *corev1.LocalObjectReference `json:"persistentVolumeClaimRef,omitempty"`
+
+	// Message is a human readable description of the current state
+	Message string `json:"message,omitempty"`
+
+	// LastTransitionTime is the last time the state changed
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty"`
+
+	// Pods lists the pods using the persistent volume claim while the access is being revoked
+	Pods []string `json:"pods,omitempty"`
 }
 
 type NamespaceAccessState string
@@ -107,6 +463,117 @@ const (
 
 	// NamespaceAccessReady - used to indicate the namespace access is ready
 	NamespaceAccessReady NamespaceAccessState = "Ready"
+
+	// NamespaceAccessNamespaceNotFound - used to indicate the namespace does not exist
+	NamespaceAccessNamespaceNotFound NamespaceAccessState = "NamespaceNotFound"
+
+	// NamespaceAccessNamespaceTerminating - used to indicate the namespace is being deleted
+	NamespaceAccessNamespaceTerminating NamespaceAccessState = "NamespaceTerminating"
+
+	// NamespaceAccessPVConflict - used to indicate the persistent volume exists but cannot be used for this access
+	NamespaceAccessPVConflict NamespaceAccessState = "PVConflict"
+
+	// NamespaceAccessPVCConflict - used to indicate the persistent volume claim exists but is not managed by this file system
+	NamespaceAccessPVCConflict NamespaceAccessState = "PVCConflict"
+
+	// NamespaceAccessPVCBindFailed - used to indicate the persistent volume claim cannot be bound to its persistent volume
+	NamespaceAccessPVCBindFailed NamespaceAccessState = "PVCBindFailed"
+
+	// NamespaceAccessRolloverPending - used to indicate the persistent volume claim is waiting to move to a persistent volume with the current export path
+	NamespaceAccessRolloverPending NamespaceAccessState = "RolloverPending"
+
+	// NamespaceAccessRevoking - used to indicate the access was removed and is waiting for the pods using it to finish
+	NamespaceAccessRevoking NamespaceAccessState = "Revoking"
+
+	// NamespaceAccessExpired - used to indicate the access expired and was revoked
+	NamespaceAccessExpired NamespaceAccessState = "Expired"
+
+	// NamespaceAccessError - used to indicate an unexpected error occurred while granting the access
+	NamespaceAccessError NamespaceAccessState = "Error"
+)
+
+// NamespaceAccessStates returns every NamespaceAccessState. A new state must be added here too, since the states
+// reported by the metrics come from it.
+func NamespaceAccessStates() []NamespaceAccessState {
+	return []NamespaceAccessState{
+		NamespaceAccessPending,
+		NamespaceAccessReady,
+		NamespaceAccessNamespaceNotFound,
+		NamespaceAccessNamespaceTerminating,
+		NamespaceAccessPVConflict,
+		NamespaceAccessPVCConflict,
+		NamespaceAccessPVCBindFailed,
+		NamespaceAccessRolloverPending,
+		NamespaceAccessRevoking,
+		NamespaceAccessExpired,
+		NamespaceAccessError,
+	}
+}
+
+const (
+	// ConditionReady is true when every namespace access in the specification is ready for use
+	ConditionReady = "Ready"
+
+	// ConditionDegraded is true when the last reconcile failed or one or more namespace accesses are not ready
+	ConditionDegraded = "Degraded"
+
+	// ConditionNamespacesReconciled is true when the namespace accesses in the status match the specification
+	ConditionNamespacesReconciled = "NamespacesReconciled"
+
+	// ConditionDeleting is true when the file system is being deleted
+	ConditionDeleting = "Deleting"
+
+	// ConditionStorageClassReady is true when the storage class exists and suits the file system
+	ConditionStorageClassReady = "StorageClassReady"
+)
+
+const (
+	// ConditionReasonReconciled - used when the specification was reconciled successfully
+	ConditionReasonReconciled = "Reconciled"
+
+	// ConditionReasonReconcileError - used when the last reconcile returned an error
+	ConditionReasonReconcileError = "ReconcileError"
+
+	// ConditionReasonAccessPending - used when one or more namespace accesses are not yet ready
+	ConditionReasonAccessPending = "AccessPending"
+
+	// ConditionReasonDeleting - used when the file system is being deleted
+	ConditionReasonDeleting = "Deleting"
+
+	// ConditionReasonNotDeleting - used when the file system is not being deleted
+	ConditionReasonNotDeleting = "NotDeleting"
+
+	// ConditionReasonInUse - used when deletion of the file system is waiting for pods using its claims to finish
+	ConditionReasonInUse = "InUse"
+
+	// ConditionReasonRevoking - used when one or more revoked namespace accesses are waiting for pods using them to finish
+	ConditionReasonRevoking = "Revoking"
+
+	// ConditionReasonStorageClassAvailable - used when the storage class exists and suits the file system
+	ConditionReasonStorageClassAvailable = "Available"
+
+	// ConditionReasonStorageClassNotFound - used when the storage class does not exist
+	ConditionReasonStorageClassNotFound = "NotFound"
+
+	// ConditionReasonStorageClassMismatch - used when the storage class has the wrong provisioner or binding mode
+	ConditionReasonStorageClassMismatch = "Mismatch"
+
+	// ConditionReasonStorageClassConflict - used when a managed storage class exists but is not owned by the file system
+	ConditionReasonStorageClassConflict = "Conflict"
+)
+
+const (
+	// OwnerNameLabel is applied to the persistent volumes and claims created for a LustreFileSystem
+	// and holds the name of the owning LustreFileSystem
+	OwnerNameLabel = "lus.cray.hpe.com/owner.name"
+
+	// OwnerNamespaceLabel is applied to the persistent volumes and claims created for a LustreFileSystem
+	// and holds the namespace of the owning LustreFileSystem
+	OwnerNamespaceLabel = "lus.cray.hpe.com/owner.namespace"
+
+	// ForceDeleteAnnotation, when set to "true" on a LustreFileSystem, deletes the file system and its persistent
+	// volumes and claims without waiting for the pods using the claims to finish
+	ForceDeleteAnnotation = "lus.cray.hpe.com/force-delete"
 )
 
 //+kubebuilder:object:root=true
@@ -114,8 +581,10 @@ const (
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="FSNAME",type="string",JSONPath=".spec.name",description="Lustre file system name"
 //+kubebuilder:printcolumn:name="MGSNIDS",type="string",JSONPath=".spec.mgsNids",description="List of MGS NIDs"
+//+kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type==\"Ready\")].status",description="True if all namespace accesses are ready"
 //+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 //+kubebuilder:printcolumn:name="MountRoot",type="string",JSONPath=".spec.mountRoot",priority=1,description="Mount path used to mount filesystem"
+//+kubebuilder:printcolumn:name="MGSNodes",type="string",JSONPath=".spec.mgsNodes[*].nids[0]",priority=1,description="Primary NID of each MGS node in failover order"
 //+kubebuilder:printcolumn:name="StorageClass",type="string",JSONPath=".spec.storageClassName",priority=1,description="StorageClass to use"
 
 // LustreFileSystem is the Schema for the lustrefilesystems API
@@ -127,12 +596,288 @@ type LustreFileSystem struct {
 	Status LustreFileSystemStatus `json:"status,omitempty"`
 }
 
+// accessNamePrefix returns the readable part of the names of the persistent volume and claim of an access
+func (fs *LustreFileSystem) accessNamePrefix(namespace string, mode corev1.PersistentVolumeAccessMode) string {
+	return fs.Name + "-" + namespace + "-" + strings.ToLower(string(mode))
+}
+
+// accessNameHashBytes is the number of bytes of the hash in the names of the objects of an access
+const accessNameHashBytes = 8
+
+// accessNameHash returns a hash of the parts that identify an object of an access. The parts are joined with a
+// separator that can't appear in any of them, so different parts always give a different input to the hash. The
+// names of persistent volumes are shared by every file system in the cluster, so the hash is a truncated SHA-256
+// that's long enough to make a collision between two accesses implausible.
+func accessNameHash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "/")))
+
+	return hex.EncodeToString(sum[:accessNameHashBytes])
+}
+
+// hashedName returns the prefix followed by the hash and the suffix. The prefix is truncated when the name would be
+// longer than an object name allows.
+func hashedName(prefix string, hash string, suffix string) string {
+	if length := validation.DNS1123SubdomainMaxLength - len(hash) - len(suffix) - 1; len(prefix) > length {
+		prefix = strings.TrimRight(prefix[:length], "-.")
+	}
+
+	return prefix + "-" + hash + suffix
+}
+
+// PersistentVolumeName returns the name of the persistent volume of a namespace access. Persistent volumes aren't
+// namespaced, so the readable prefix alone could be the same for two accesses, e.g. a file system 'a-b' with namespace
+// 'c' and a file system 'a' with namespace 'b-c'. A hash of the file system, namespace and mode makes it unique.
 func (fs *LustreFileSystem) PersistentVolumeName(namespace string, mode corev1.PersistentVolumeAccessMode) string {
-	return fs.Name + "-" + namespace + "-" + strings.ToLower(string(mode)) + "-pv"
+	return hashedName(fs.accessNamePrefix(namespace, mode), accessNameHash(fs.Namespace, fs.Name, namespace, string(mode)), "-pv")
 }
 
+// LegacyPersistentVolumeName returns the name of the persistent volume of a namespace access under the naming scheme
+// used before PersistentVolumeName. A claim bound to a persistent volume with this name keeps using it.
+func (fs *LustreFileSystem) LegacyPersistentVolumeName(namespace string, mode corev1.PersistentVolumeAccessMode) string {
+	return fs.accessNamePrefix(namespace, mode) + "-pv"
+}
+
+// PersistentVolumeRolloverName returns the name of the persistent volume created alongside the existing persistent
+// volume of an access when the export path changes. The name is unique to the export path.
+func (fs *LustreFileSystem) PersistentVolumeRolloverName(namespace string, mode corev1.PersistentVolumeAccessMode, exportPath string) string {
+	return hashedName(fs.accessNamePrefix(namespace, mode), accessNameHash(fs.Namespace, fs.Name, namespace, string(mode), exportPath), "-pv")
+}
+
+// PersistentVolumeClaimName returns the name of the persistent volume claim of a namespace access. Pods refer to the
+// claim by name, so the readable name is kept whenever it fits; it can't collide within the namespace since the
+// namespace and the modes are fixed parts of it. A name that is too long is truncated and a hash is appended.
 func (fs *LustreFileSystem) PersistentVolumeClaimName(namespace string, mode corev1.PersistentVolumeAccessMode) string {
-	return fs.Name + "-" + namespace + "-" + strings.ToLower(string(mode)) + "-pvc"
+	prefix := fs.accessNamePrefix(namespace, mode)
+	if name := prefix + "-pvc"; len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+
+	return hashedName(prefix, accessNameHash(fs.Namespace, fs.Name, namespace, string(mode)), "-pvc")
+}
+
+// SubdirectoryTemplateData is the data available to a subdirectory template
+type SubdirectoryTemplateData struct {
+	// Namespace is the namespace granted access
+	Namespace string
+
+	// Name is the name of the LustreFileSystem
+	Name string
+
+	// FileSystemName is the name of the Lustre file system
+	FileSystemName string
+}
+
+// AccessTemplateData is the data available to the claim name, label and annotation templates of a namespace access
+type AccessTemplateData struct {
+	SubdirectoryTemplateData
+
+	// Mode is the lowercase access mode
+	Mode string
+}
+
+// renderAccessTemplate renders the template text of a namespace access
+func (fs *LustreFileSystem) renderAccessTemplate(name string, text string, namespace string, mode corev1.PersistentVolumeAccessMode) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	data := AccessTemplateData{
+		SubdirectoryTemplateData: SubdirectoryTemplateData{Namespace: namespace, Name: fs.Name, FileSystemName: fs.Spec.Name},
+		Mode:                     strings.ToLower(string(mode)),
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+// NamespaceClaimName renders the name of the persistent volume claim of a namespace access with the given namespace
+// specification, or returns the generated name when the specification doesn't have a claim name.
+func (fs *LustreFileSystem) NamespaceClaimName(namespace string, spec LustreFileSystemNamespaceSpec, mode corev1.PersistentVolumeAccessMode) (string, error) {
+	if len(spec.ClaimName) == 0 {
+		return fs.PersistentVolumeClaimName(namespace, mode), nil
+	}
+
+	name, err := fs.renderAccessTemplate("claimName", spec.ClaimName, namespace, mode)
+	if err != nil {
+		return "", err
+	}
+
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) != 0 {
+		return "", fmt.Errorf("claim name '%s' is invalid: %s", name, strings.Join(errs, "; "))
+	}
+
+	return name, nil
+}
+
+// NamespaceLabels renders the labels added to the persistent volume and claim of a namespace access with the given
+// namespace specification
+func (fs *LustreFileSystem) NamespaceLabels(namespace string, spec LustreFileSystemNamespaceSpec, mode corev1.PersistentVolumeAccessMode) (map[string]string, error) {
+	labels := make(map[string]string, len(spec.Labels))
+	for key, text := range spec.Labels {
+		if errs := validation.IsQualifiedName(key); len(errs) != 0 {
+			return nil, fmt.Errorf("label key '%s' is invalid: %s", key, strings.Join(errs, "; "))
+		}
+
+		if key == OwnerNameLabel || key == OwnerNamespaceLabel {
+			return nil, fmt.Errorf("label key '%s' is reserved for the owner of the persistent volume and claim", key)
+		}
+
+		value, err := fs.renderAccessTemplate("labels", text, namespace, mode)
+		if err != nil {
+			return nil, err
+		}
+
+		if errs := validation.IsValidLabelValue(value); len(errs) != 0 {
+			return nil, fmt.Errorf("label '%s' value '%s' is invalid: %s", key, value, strings.Join(errs, "; "))
+		}
+
+		labels[key] = value
+	}
+
+	return labels, nil
+}
+
+// NamespaceAnnotations renders the annotations added to the persistent volume and claim of a namespace access with
+// the given namespace specification
+func (fs *LustreFileSystem) NamespaceAnnotations(namespace string, spec LustreFileSystemNamespaceSpec, mode corev1.PersistentVolumeAccessMode) (map[string]string, error) {
+	annotations := make(map[string]string, len(spec.Annotations))
+	for key, text := range spec.Annotations {
+		if errs := validation.IsQualifiedName(strings.ToLower(key)); len(errs) != 0 {
+			return nil, fmt.Errorf("annotation key '%s' is invalid: %s", key, strings.Join(errs, "; "))
+		}
+
+		value, err := fs.renderAccessTemplate("annotations", text, namespace, mode)
+		if err != nil {
+			return nil, err
+		}
+
+		annotations[key] = value
+	}
+
+	return annotations, nil
+}
+
+// NamespaceSubdirectory renders the subdirectory exported to a namespace with the given namespace specification.
+// The result is a clean relative path, or an empty string when the root of the file system is exported.
+func (fs *LustreFileSystem) NamespaceSubdirectory(namespace string, spec LustreFileSystemNamespaceSpec) (string, error) {
+	text := spec.Subdirectory
+	if len(text) == 0 {
+		text = fs.Spec.Subdirectory
+	}
+
+	if len(text) == 0 {
+		return "", nil
+	}
+
+	tmpl, err := template.New("subdirectory").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, SubdirectoryTemplateData{Namespace: namespace, Name: fs.Name, FileSystemName: fs.Spec.Name}); err != nil {
+		return "", err
+	}
+
+	subdirectory := filepath.Clean(b.String())
+	if filepath.IsAbs(subdirectory) || subdirectory == ".." || strings.HasPrefix(subdirectory, "../") {
+		return "", fmt.Errorf("subdirectory '%s' must be relative to the root of the file system", b.String())
+	}
+
+	if subdirectory == "." {
+		return "", nil
+	}
+
+	return subdirectory, nil
+}
+
+// MgsNodesFromNids returns the MGS nodes described by a list of comma- and colon- separated MGS NIDs
+func MgsNodesFromNids(mgsNids string) []LustreFileSystemMgsNode {
+	if len(mgsNids) == 0 {
+		return nil
+	}
+
+	nodes := []LustreFileSystemMgsNode{}
+	for _, nids := range SplitNids(mgsNids) {
+		nodes = append(nodes, LustreFileSystemMgsNode{Nids: nids})
+	}
+
+	return nodes
+}
+
+// MgsNidsFromNodes returns the list of comma- and colon- separated MGS NIDs describing the MGS nodes
+func MgsNidsFromNodes(nodes []LustreFileSystemMgsNode) string {
+	mgsNids := make([]string, len(nodes))
+	for i, node := range nodes {
+		mgsNids[i] = strings.Join(node.Nids, ",")
+	}
+
+	return strings.Join(mgsNids, ":")
+}
+
+// MgsNidList returns the list of MGS NIDs used to mount the file system. The MGS nodes are used when present,
+// otherwise the file system predates them and the MGS NIDs are used.
+func (fs *LustreFileSystem) MgsNidList() string {
+	if len(fs.Spec.MgsNodes) != 0 {
+		return MgsNidsFromNodes(fs.Spec.MgsNodes)
+	}
+
+	return fs.Spec.MgsNids
+}
+
+// ExportPath returns the Lustre path, including the MGS NIDs, for a subdirectory of the file system
+func (fs *LustreFileSystem) ExportPath(subdirectory string) string {
+	if len(subdirectory) == 0 {
+		return fs.MgsNidList() + ":/" + fs.Spec.Name
+	}
+
+	return fs.MgsNidList() + ":/" + fs.Spec.Name + "/" + subdirectory
+}
+
+// RevocationGracePeriod returns how long a revoked namespace access waits for the pods using it to finish
+func (fs *LustreFileSystem) RevocationGracePeriod() time.Duration {
+	if fs.Spec.RevocationGracePeriodSeconds != nil {
+		return time.Duration(*fs.Spec.RevocationGracePeriodSeconds) * time.Second
+	}
+
+	return GetDefaults().RevocationGracePeriod
+}
+
+// NamespaceMountOptions returns the mount options for a namespace with the given namespace specification
+func (fs *LustreFileSystem) NamespaceMountOptions(spec LustreFileSystemNamespaceSpec) []string {
+	if spec.MountOptions != nil {
+		return spec.MountOptions
+	}
+
+	return fs.Spec.MountOptions
+}
+
+// NamespaceVolumeAttributes returns the CSI volume attributes for a namespace with the given namespace specification
+func (fs *LustreFileSystem) NamespaceVolumeAttributes(spec LustreFileSystemNamespaceSpec) map[string]string {
+	if len(fs.Spec.VolumeAttributes) == 0 && len(spec.VolumeAttributes) == 0 {
+		return nil
+	}
+
+	attributes := make(map[string]string, len(fs.Spec.VolumeAttributes)+len(spec.VolumeAttributes))
+	for key, value := range fs.Spec.VolumeAttributes {
+		attributes[key] = value
+	}
+
+	for key, value := range spec.VolumeAttributes {
+		attributes[key] = value
+	}
+
+	return attributes
+}
+
+// AsSelector converts the namespace selector to a labels.Selector. An empty selector matches every namespace.
+func (s *LustreFileSystemNamespaceSelector) AsSelector() (labels.Selector, error) {
+	return metav1.LabelSelectorAsSelector(&s.LabelSelector)
 }
 
 func (fs *LustreFileSystem) GetStatus() updater.Status[*LustreFileSystemStatus] {
@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2024 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -20,17 +20,26 @@
 package v1beta1
 
 import (
-	"net"
-	"net/url"
+	"context"
+	"fmt"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -39,8 +48,57 @@ import (
 // log is for logging in this package.
 var lustrefilesystemlog = logf.Log.WithName("lustrefilesystem-resource")
 
+// c is the client used by the webhooks to look up cluster objects
+var c client.Client
+
+// DefaultStorageClassName is the storage class the CRD gives a LustreFileSystem that doesn't specify one
+const DefaultStorageClassName = "nnf-lustre-fs"
+
+// LustreFileSystemDefaults holds the cluster configured defaults applied to a LustreFileSystem. It's operator
+// configuration rather than an API type, so it has no generated deep copy.
+// +kubebuilder:object:generate=false
+type LustreFileSystemDefaults struct {
+	// CSIDriver is the name of the CSI driver used when a LustreFileSystem doesn't specify one
+	CSIDriver string
+
+	// StorageClassName is the name of the storage class used when a LustreFileSystem doesn't specify one
+	StorageClassName string
+
+	// Modes are the access modes granted to a namespace of a LustreFileSystem that doesn't list any
+	Modes []corev1.PersistentVolumeAccessMode
+
+	// MountOptions are the mount options used when a LustreFileSystem doesn't specify any
+	MountOptions []string
+
+	// RevocationGracePeriod is how long a revoked namespace access waits for the pods using it to finish when a
+	// LustreFileSystem doesn't specify a grace period
+	RevocationGracePeriod time.Duration
+}
+
+var (
+	defaultsLock sync.RWMutex
+	defaults     LustreFileSystemDefaults
+)
+
+// SetDefaults sets the cluster configured defaults applied to a LustreFileSystem
+func SetDefaults(d LustreFileSystemDefaults) {
+	defaultsLock.Lock()
+	defer defaultsLock.Unlock()
+
+	defaults = d
+}
+
+// GetDefaults returns the cluster configured defaults applied to a LustreFileSystem
+func GetDefaults() LustreFileSystemDefaults {
+	defaultsLock.RLock()
+	defer defaultsLock.RUnlock()
+
+	return defaults
+}
+
 // SetupWebhookWithManager will setup the manager to manage the webhooks
 func (r *LustreFileSystem) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	c = mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -48,10 +106,93 @@ func (r *LustreFileSystem) SetupWebhookWithManager(mgr ctrl.Manager) error {
 
 // TODO(user): EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
 
-// TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
+//+kubebuilder:webhook:path=/mutate-lus-cray-hpe-com-v1beta1-lustrefilesystem,mutating=true,failurePolicy=fail,sideEffects=None,groups=lus.cray.hpe.com,resources=lustrefilesystems,verbs=create;update,versions=v1beta1,name=mlustrefilesystem.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &LustreFileSystem{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *LustreFileSystem) Default() {
+	lustrefilesystemlog.Info("default", "name", r.Name)
+
+	defaults := GetDefaults()
+	if len(r.Spec.CSIDriver) == 0 {
+		r.Spec.CSIDriver = defaults.CSIDriver
+	}
+
+	// The CRD fills in its default storage class before the webhook runs, so a new file system with that storage
+	// class takes the operator's default instead. The storage class can't change once the file system exists.
+	if r.CreationTimestamp.IsZero() && len(defaults.StorageClassName) != 0 &&
+		(len(r.Spec.StorageClassName) == 0 || r.Spec.StorageClassName == DefaultStorageClassName) {
+		r.Spec.StorageClassName = defaults.StorageClassName
+	}
+
+	if len(r.Spec.MountOptions) == 0 && len(defaults.MountOptions) != 0 {
+		r.Spec.MountOptions = slices.Clone(defaults.MountOptions)
+	}
+
+	for namespace, spec := range r.Spec.Namespaces {
+		if len(spec.Modes) == 0 && len(defaults.Modes) != 0 {
+			spec.Modes = slices.Clone(defaults.Modes)
+			r.Spec.Namespaces[namespace] = spec
+		}
+	}
+
+	if len(r.Spec.MountRoot) != 0 {
+		r.Spec.MountRoot = filepath.Clean(r.Spec.MountRoot)
+	}
+
+	r.Spec.MgsNids = cleanMgsNids(r.Spec.MgsNids)
+	r.Spec.MgsNodes = cleanMgsNodes(r.Spec.MgsNodes)
+
+	// Keep the MGS NIDs and MGS nodes consistent by filling in whichever wasn't specified
+	if len(r.Spec.MgsNodes) == 0 {
+		r.Spec.MgsNodes = MgsNodesFromNids(r.Spec.MgsNids)
+	} else if len(r.Spec.MgsNids) == 0 {
+		r.Spec.MgsNids = MgsNidsFromNodes(r.Spec.MgsNodes)
+	}
+}
+
+// cleanMgsNodes returns the MGS nodes with the whitespace around each NID trimmed
+func cleanMgsNodes(mgsNodes []LustreFileSystemMgsNode) []LustreFileSystemMgsNode {
+	if mgsNodes == nil {
+		return nil
+	}
+
+	nodes := make([]LustreFileSystemMgsNode, len(mgsNodes))
+	for i := range mgsNodes {
+		mgsNodes[i].DeepCopyInto(&nodes[i])
+		for j := range nodes[i].Nids {
+			nodes[i].Nids[j] = strings.TrimSpace(nodes[i].Nids[j])
+		}
+	}
+
+	return nodes
+}
+
+// cleanMgsNids trims the whitespace around each NID and drops the empty NIDs and nodes left by stray
+// separators. The order of the nodes and of the NIDs of each node is kept since the first node is the
+// primary MGS and LNet tries the NIDs of a node in order.
+func cleanMgsNids(mgsNids string) string {
+	nodes := []LustreFileSystemMgsNode{}
+	for _, nids := range SplitNids(mgsNids) {
+		node := LustreFileSystemMgsNode{}
+		for _, nid := range nids {
+			if nid = strings.TrimSpace(nid); len(nid) != 0 {
+				node.Nids = append(node.Nids, nid)
+			}
+		}
+
+		if len(node.Nids) != 0 {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return MgsNidsFromNodes(nodes)
+}
+
 // NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
 // Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
-//+kubebuilder:webhook:path=/validate-lus-cray-hpe-com-v1beta1-lustrefilesystem,mutating=false,failurePolicy=fail,sideEffects=None,groups=lus.cray.hpe.com,resources=lustrefilesystems,verbs=create;update,versions=v1beta1,name=vlustrefilesystem.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-lus-cray-hpe-com-v1beta1-lustrefilesystem,mutating=false,failurePolicy=fail,sideEffects=None,groups=lus.cray.hpe.com,resources=lustrefilesystems,verbs=create;update;delete,versions=v1beta1,name=vlustrefilesystem.kb.io,admissionReviewVersions=v1
 
 var _ webhook.Validator = &LustreFileSystem{}
 
@@ -59,17 +200,45 @@ var _ webhook.Validator = &LustreFileSystem{}
 func (r *LustreFileSystem) ValidateCreate() (admission.Warnings, error) {
 	lustrefilesystemlog.Info("validate create", "name", r.Name)
 
+	// The name and namespace are used as label values on the persistent volumes and claims
+	// created for this file system, so they must be valid label values.
+	if msgs := validation.IsValidLabelValue(r.Name); len(msgs) != 0 {
+		f := field.NewPath("metadata").Child("name")
+		return nil, errors.NewInvalid(
+			schema.GroupKind{Group: "", Kind: "LustreFileSystem"},
+			r.Name,
+			field.ErrorList{field.Invalid(f, r.Name, strings.Join(msgs, "; "))},
+		)
+	}
+
 	return nil, r.validateLustreFileSystem()
 }
 
 func (r *LustreFileSystem) validateLustreFileSystem() error {
 	var errList field.ErrorList
-	if err := r.validateMgsNids(); err != nil {
+	errList = append(errList, r.validateMgsNids()...)
+	errList = append(errList, r.validateMgsNodes()...)
+	if err := r.validateMountRoot(); err != nil {
 		errList = append(errList, err)
 	}
-	if err := r.validateMountRoot(); err != nil {
+	if err := r.validateNamespaceSelector(); err != nil {
 		errList = append(errList, err)
 	}
+	if err := r.validateCSIDriver(); err != nil {
+		errList = append(errList, err)
+	}
+	if len(r.Spec.StorageClassName) == 0 {
+		errList = append(errList, field.Required(field.NewPath("spec").Child("storageClassName"), "no storage class specified and the operator has no default storage class"))
+	}
+	errList = append(errList, r.validateModes()...)
+	errList = append(errList, r.validateMountOptions()...)
+	errList = append(errList, r.validateSubdirectories()...)
+	errList = append(errList, r.validateAccessTemplates()...)
+	errList = append(errList, r.validateAccessPolicy()...)
+	errList = append(errList, r.validateExpiry()...)
+	errList = append(errList, r.validateQuotas()...)
+	errList = append(errList, r.validateUniqueness()...)
+	errList = append(errList, r.validateStorageClassPolicy()...)
 
 	if len(errList) != 0 {
 		return errors.NewInvalid(
@@ -82,29 +251,54 @@ func (r *LustreFileSystem) validateLustreFileSystem() error {
 	return nil
 }
 
-func (r *LustreFileSystem) validateMgsNids() *field.Error {
+// validateMgsNids checks each NID of the MGS nodes. NIDs are indexed in the order they appear in the list.
+func (r *LustreFileSystem) validateMgsNids() field.ErrorList {
+	var errList field.ErrorList
 
-	re := regexp.MustCompile(`[:,]`)
 	f := field.NewPath("spec").Child("mgsNids")
+	if len(r.Spec.MgsNids) == 0 {
+		return append(errList, field.Required(f, "at least one MGS NID is required"))
+	}
 
-	for _, nid := range re.Split(r.Spec.MgsNids, -1) {
-		if !strings.Contains(nid, "@") {
-			return field.Invalid(f, nid, "must be a valid mgsNid format [HOST]@[INTERFACE]")
+	index := 0
+	for _, node := range SplitNids(r.Spec.MgsNids) {
+		for _, nid := range node {
+			if _, err := ParseNid(nid); err != nil {
+				errList = append(errList, field.Invalid(f.Index(index), nid, err.Error()))
+			}
+
+			index++
 		}
+	}
 
-		hostname := strings.SplitN(nid, "@", 2)[0]
-		if net.ParseIP(hostname) != nil {
-			// Valid IP
-			return nil
+	return errList
+}
+
+// validateMgsNodes checks that the MGS nodes describe the same NIDs as the MGS NIDs. The NIDs themselves are
+// checked with the MGS NIDs, so they're only reported here when the two differ.
+func (r *LustreFileSystem) validateMgsNodes() field.ErrorList {
+	var errList field.ErrorList
+
+	f := field.NewPath("spec").Child("mgsNodes")
+	for i, node := range r.Spec.MgsNodes {
+		if len(node.Nids) == 0 {
+			errList = append(errList, field.Required(f.Index(i).Child("nids"), "an MGS node must have at least one NID"))
 		}
+	}
+
+	if len(errList) != 0 || equality.Semantic.DeepEqual(r.Spec.MgsNodes, MgsNodesFromNids(r.Spec.MgsNids)) {
+		return errList
+	}
 
-		_, err := url.Parse(hostname)
-		if err != nil {
-			return field.Invalid(f, nid, "invalid hostname format")
+	for i, node := range r.Spec.MgsNodes {
+		for j, nid := range node.Nids {
+			if _, err := ParseNid(nid); err != nil {
+				errList = append(errList, field.Invalid(f.Index(i).Child("nids").Index(j), nid, err.Error()))
+			}
 		}
 	}
 
-	return nil
+	return append(errList, field.Invalid(f, MgsNidsFromNodes(r.Spec.MgsNodes), fmt.Sprintf("must describe the same NIDs as mgsNids '%s'", r.Spec.MgsNids)))
 }
 
 func (r *LustreFileSystem) validateMountRoot() *field.Error {
@@ -132,11 +326,23 @@ func (r *LustreFileSystem) ValidateUpdate(obj runtime.Object) (admission.Warning
 		return nil, immutableError("Name")
 	}
 
-	if r.Spec.MgsNids != old.Spec.MgsNids {
+	// The MGS NIDs and nodes are cleaned by the defaulter, so a file system that predates that may be updated
+	oldMgsNids := cleanMgsNids(old.Spec.MgsNids)
+	oldMgsNodes := cleanMgsNodes(old.Spec.MgsNodes)
+
+	// The MGS NIDs may only change when the claims can be rolled over to new persistent volumes
+	rollover := r.Spec.MgsNidsUpdatePolicy == MgsNidsUpdateRollover
+	if r.Spec.MgsNids != oldMgsNids && !rollover {
 		return nil, immutableError("MgsNids")
 	}
 
-	if r.Spec.MountRoot != old.Spec.MountRoot {
+	// The MGS nodes may be added to a file system that predates the field, but can't be changed after that
+	if len(oldMgsNodes) != 0 && !equality.Semantic.DeepEqual(r.Spec.MgsNodes, oldMgsNodes) && !rollover {
+		return nil, immutableError("MgsNodes")
+	}
+
+	// The mount root is cleaned by the defaulter, so a file system that predates that may be updated
+	if r.Spec.MountRoot != filepath.Clean(old.Spec.MountRoot) {
 		return nil, immutableError("MountRoot")
 	}
 
@@ -144,13 +350,527 @@ func (r *LustreFileSystem) ValidateUpdate(obj runtime.Object) (admission.Warning
 		return nil, immutableError("StorageClassName")
 	}
 
+	// The CSI driver may be set on a file system that predates the field, but can't be changed after that
+	if len(old.Spec.CSIDriver) != 0 && r.Spec.CSIDriver != old.Spec.CSIDriver {
+		return nil, immutableError("CSIDriver")
+	}
+
+	if err := r.validateNamespaceSelector(); err != nil {
+		return nil, err
+	}
+
+	errList := append(r.validateModes(), r.validateMountOptions()...)
+	errList = append(errList, r.validateSubdirectories()...)
+	errList = append(errList, r.validateAccessTemplates()...)
+	errList = append(errList, r.validateClaimNamesUnchanged(old)...)
+	errList = append(errList, r.validateAccessPolicy()...)
+	errList = append(errList, r.validateExpiry()...)
+	errList = append(errList, r.validateQuotas()...)
+	if r.Spec.MgsNids != oldMgsNids {
+		errList = append(errList, r.validateMgsNids()...)
+	}
+	errList = append(errList, r.validateMgsNodes()...)
+	if r.Spec.MgsNids != oldMgsNids {
+		errList = append(errList, r.validateUniqueness()...)
+	}
+	if r.Spec.StorageClassPolicy != old.Spec.StorageClassPolicy {
+		errList = append(errList, r.validateStorageClassPolicy()...)
+	}
+	if len(errList) != 0 {
+		return nil, errors.NewInvalid(
+			schema.GroupKind{Group: "", Kind: "LustreFileSystem"},
+			r.Name,
+			errList,
+		)
+	}
+
+	if r.Spec.CSIDriver != old.Spec.CSIDriver {
+		if err := r.validateCSIDriver(); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type. The deletion is always
+// allowed, but it waits for the pods using the claims of the file system to finish, so they're listed in a warning.
 func (r *LustreFileSystem) ValidateDelete() (admission.Warnings, error) {
 	lustrefilesystemlog.Info("validate delete", "name", r.Name)
 
-	// TODO(user): fill in your validation logic upon object deletion.
-	return nil, nil
+	if c == nil || r.GetAnnotations()[ForceDeleteAnnotation] == "true" {
+		return nil, nil
+	}
+
+	pods, err := r.podsUsingClaims()
+	if err != nil {
+		// The warning only explains the wait, so the deletion goes ahead without it
+		lustrefilesystemlog.Error(err, "unable to find the pods using the claims", "name", r.Name)
+		return nil, nil
+	}
+
+	if len(pods) == 0 {
+		return nil, nil
+	}
+
+	return admission.Warnings{fmt.Sprintf("deletion waits for %d pod(s) using the file system to finish: %s; set the '%s' annotation to 'true' to delete anyway",
+		len(pods), strings.Join(pods, ", "), ForceDeleteAnnotation)}, nil
+}
+
+// podsUsingClaims returns the pods, as namespace/name, that use a claim of a namespace access in the status. These
+// are the pods the controller lists in DeletionBlockedBy once the file system is being deleted.
+func (r *LustreFileSystem) podsUsingClaims() ([]string, error) {
+	names := []string{}
+	for namespace, namespaceStatus := range r.Status.Namespaces {
+		claimNames := []string{}
+		for mode, access := range namespaceStatus.Modes {
+			if access.PersistentVolumeClaimRef != nil {
+				claimNames = append(claimNames, access.PersistentVolumeClaimRef.Name)
+			} else {
+				claimNames = append(claimNames, r.PersistentVolumeClaimName(namespace, mode))
+			}
+		}
+
+		pods := &corev1.PodList{}
+		if err := c.List(context.TODO(), pods, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+
+		for _, pod := range pods.Items {
+			if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+
+			for _, volume := range pod.Spec.Volumes {
+				if volume.PersistentVolumeClaim != nil && slices.Contains(claimNames, volume.PersistentVolumeClaim.ClaimName) {
+					names = append(names, namespace+"/"+pod.Name)
+					break
+				}
+			}
+		}
+	}
+
+	slices.Sort(names)
+	return names, nil
+}
+
+func (r *LustreFileSystem) validateNamespaceSelector() *field.Error {
+	if r.Spec.NamespaceSelector == nil {
+		return nil
+	}
+
+	f := field.NewPath("spec").Child("namespaceSelector")
+	if _, err := r.Spec.NamespaceSelector.AsSelector(); err != nil {
+		return field.Invalid(f, r.Spec.NamespaceSelector.LabelSelector, err.Error())
+	}
+
+	return nil
+}
+
+// validateAccessPolicy checks the namespaces and the namespace selector of the access policy
+func (r *LustreFileSystem) validateAccessPolicy() field.ErrorList {
+	var errList field.ErrorList
+
+	policy := r.Spec.AccessPolicy
+	if policy == nil {
+		return nil
+	}
+
+	f := field.NewPath("spec").Child("accessPolicy")
+	for i, namespace := range policy.Namespaces {
+		if msgs := validation.IsDNS1123Label(namespace); len(msgs) != 0 {
+			errList = append(errList, field.Invalid(f.Child("namespaces").Index(i), namespace, strings.Join(msgs, "; ")))
+		}
+	}
+
+	if policy.NamespaceSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(policy.NamespaceSelector); err != nil {
+			errList = append(errList, field.Invalid(f.Child("namespaceSelector"), policy.NamespaceSelector, err.Error()))
+		}
+	}
+
+	return errList
+}
+
+// validateExpiry checks that each namespace sets at most one of its expiry time and TTL, and that the TTL is positive
+func (r *LustreFileSystem) validateExpiry() field.ErrorList {
+	var errList field.ErrorList
+
+	f := field.NewPath("spec").Child("namespaces")
+	for namespace, spec := range r.Spec.Namespaces {
+		if spec.ExpiresAt != nil && spec.TTL != nil {
+			errList = append(errList, field.Forbidden(f.Key(namespace).Child("ttl"), "only one of expiresAt and ttl may be set"))
+		}
+
+		if spec.TTL != nil && spec.TTL.Duration <= 0 {
+			errList = append(errList, field.Invalid(f.Key(namespace).Child("ttl"), spec.TTL.Duration.String(), "must be greater than zero"))
+		}
+	}
+
+	return errList
+}
+
+// validateQuotas checks that each namespace with a quota has a subdirectory for the project quota to apply to, and
+// that the limits aren't negative
+func (r *LustreFileSystem) validateQuotas() field.ErrorList {
+	var errList field.ErrorList
+
+	f := field.NewPath("spec").Child("namespaces")
+	for namespace, spec := range r.Spec.Namespaces {
+		if spec.Quota == nil {
+			continue
+		}
+
+		// An invalid subdirectory is reported by validateSubdirectories
+		if subdirectory, err := r.NamespaceSubdirectory(namespace, spec); err == nil && (len(subdirectory) == 0 || subdirectory == ".") {
+			errList = append(errList, field.Forbidden(f.Key(namespace).Child("quota"), "a quota requires the namespace to have a subdirectory"))
+		}
+
+		if limit := spec.Quota.BlockLimit; limit != nil && limit.Sign() < 0 {
+			errList = append(errList, field.Invalid(f.Key(namespace).Child("quota").Child("blockLimit"), limit.String(), "must not be negative"))
+		}
+	}
+
+	return errList
+}
+
+// validateCSIDriver checks that the CSI driver is set and is installed in the cluster
+func (r *LustreFileSystem) validateCSIDriver() *field.Error {
+	f := field.NewPath("spec").Child("csiDriver")
+	if len(r.Spec.CSIDriver) == 0 {
+		return field.Required(f, "no CSI driver specified and the operator has no default CSI driver")
+	}
+
+	if c == nil {
+		return nil
+	}
+
+	driver := &storagev1.CSIDriver{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: r.Spec.CSIDriver}, driver); err != nil {
+		if errors.IsNotFound(err) {
+			return field.NotFound(f, r.Spec.CSIDriver)
+		}
+
+		return field.InternalError(f, err)
+	}
+
+	return nil
+}
+
+// unsupportedModes are access modes that are rejected because they can't be honored for a Lustre file system
+var unsupportedModes = map[corev1.PersistentVolumeAccessMode]string{
+	corev1.ReadWriteOncePod: "a Lustre file system is shared by every pod in the namespace through a single claim, " +
+		"so the claim can't be limited to one pod; use 'ReadWriteOnce' or 'ReadWriteMany' instead",
+}
+
+// supportedModes are the access modes a Lustre file system can be granted with
+var supportedModes = []string{
+	string(corev1.ReadWriteMany),
+	string(corev1.ReadWriteOnce),
+	string(corev1.ReadOnlyMany),
+}
+
+// validateMode checks that the access mode is supported
+func validateMode(f *field.Path, mode corev1.PersistentVolumeAccessMode) *field.Error {
+	switch {
+	case len(unsupportedModes[mode]) != 0:
+		return field.Forbidden(f, fmt.Sprintf("access mode '%s' is not allowed: %s", mode, unsupportedModes[mode]))
+	case !slices.Contains(supportedModes, string(mode)):
+		return field.NotSupported(f, mode, supportedModes)
+	}
+
+	return nil
+}
+
+// validateModes checks the access modes of each namespace, of the namespace selector, and of the access policy
+func (r *LustreFileSystem) validateModes() field.ErrorList {
+	var errList field.ErrorList
+
+	validate := func(f *field.Path, modes []corev1.PersistentVolumeAccessMode) {
+		seen := map[corev1.PersistentVolumeAccessMode]bool{}
+		for i, mode := range modes {
+			if err := validateMode(f.Index(i), mode); err != nil {
+				errList = append(errList, err)
+			} else if seen[mode] {
+				errList = append(errList, field.Duplicate(f.Index(i), mode))
+			}
+
+			seen[mode] = true
+		}
+	}
+
+	f := field.NewPath("spec")
+	for namespace, spec := range r.Spec.Namespaces {
+		validate(f.Child("namespaces").Key(namespace).Child("modes"), spec.Modes)
+	}
+
+	if r.Spec.NamespaceSelector != nil {
+		validate(f.Child("namespaceSelector").Child("modes"), r.Spec.NamespaceSelector.Modes)
+	}
+
+	if r.Spec.AccessPolicy != nil {
+		validate(f.Child("accessPolicy").Child("modes"), r.Spec.AccessPolicy.Modes)
+	}
+
+	return errList
+}
+
+// unsafeMountOptions are mount options that are rejected because they change the semantics of the mount
+// in ways the CSI driver doesn't expect, weaken the security of the client, or break coherency across clients
+var unsafeMountOptions = map[string]string{
+	"remount":    "the CSI driver performs the mount",
+	"bind":       "the CSI driver performs the mount",
+	"rbind":      "the CSI driver performs the mount",
+	"suid":       "set-user-ID binaries must not be honored on a shared file system",
+	"dev":        "device files must not be honored on a shared file system",
+	"localflock": "locks would not be coherent across clients; use 'flock' instead",
+}
+
+// validateMountOptions checks the mount options and volume attributes of the file system and each namespace
+func (r *LustreFileSystem) validateMountOptions() field.ErrorList {
+	var errList field.ErrorList
+
+	validate := func(f *field.Path, options []string, attributes map[string]string) {
+		for i, option := range options {
+			name := strings.SplitN(option, "=", 2)[0]
+			switch {
+			case len(option) == 0:
+				errList = append(errList, field.Invalid(f.Child("mountOptions").Index(i), option, "mount option must not be empty"))
+			case strings.ContainsAny(option, ", \t\n"):
+				errList = append(errList, field.Invalid(f.Child("mountOptions").Index(i), option, "each mount option must be a separate entry without commas or whitespace"))
+			case len(unsafeMountOptions[name]) != 0:
+				errList = append(errList, field.Forbidden(f.Child("mountOptions").Index(i), fmt.Sprintf("mount option '%s' is not allowed: %s", option, unsafeMountOptions[name])))
+			}
+		}
+
+		for key := range attributes {
+			if len(key) == 0 {
+				errList = append(errList, field.Invalid(f.Child("volumeAttributes"), key, "volume attribute key must not be empty"))
+			}
+		}
+	}
+
+	f := field.NewPath("spec")
+	validate(f, r.Spec.MountOptions, r.Spec.VolumeAttributes)
+
+	for namespace, spec := range r.Spec.Namespaces {
+		validate(f.Child("namespaces").Key(namespace), spec.MountOptions, spec.VolumeAttributes)
+	}
+
+	return errList
+}
+
+// validateSubdirectories checks that the default and per-namespace subdirectory templates render to paths
+// within the file system
+func (r *LustreFileSystem) validateSubdirectories() field.ErrorList {
+	var errList field.ErrorList
+
+	f := field.NewPath("spec")
+	if len(r.Spec.Subdirectory) != 0 {
+		// The default is rendered with a placeholder since it applies to any namespace
+		if _, err := r.NamespaceSubdirectory("namespace", LustreFileSystemNamespaceSpec{}); err != nil {
+			errList = append(errList, field.Invalid(f.Child("subdirectory"), r.Spec.Subdirectory, err.Error()))
+		}
+	}
+
+	for namespace, spec := range r.Spec.Namespaces {
+		if len(spec.Subdirectory) == 0 {
+			continue
+		}
+
+		if _, err := r.NamespaceSubdirectory(namespace, spec); err != nil {
+			errList = append(errList, field.Invalid(f.Child("namespaces").Key(namespace).Child("subdirectory"), spec.Subdirectory, err.Error()))
+		}
+	}
+
+	return errList
+}
+
+// validateAccessTemplates checks that the claim name, label and annotation templates of each namespace and the
+// namespace selector render for every mode, and that the claim names of the modes of a namespace are distinct
+func (r *LustreFileSystem) validateAccessTemplates() field.ErrorList {
+	var errList field.ErrorList
+
+	validate := func(f *field.Path, namespace string, spec LustreFileSystemNamespaceSpec) {
+		claimNames := map[string]corev1.PersistentVolumeAccessMode{}
+		for _, mode := range spec.Modes {
+			claimName, err := r.NamespaceClaimName(namespace, spec, mode)
+			if err != nil {
+				errList = append(errList, field.Invalid(f.Child("claimName"), spec.ClaimName, err.Error()))
+				break
+			}
+
+			if other, found := claimNames[claimName]; found {
+				errList = append(errList, field.Invalid(f.Child("claimName"), spec.ClaimName, fmt.Sprintf("modes %s and %s have the same claim name '%s'; use {{.Mode}} in the claim name", other, mode, claimName)))
+				break
+			}
+
+			claimNames[claimName] = mode
+		}
+
+		for _, mode := range spec.Modes {
+			if _, err := r.NamespaceLabels(namespace, spec, mode); err != nil {
+				errList = append(errList, field.Invalid(f.Child("labels"), spec.Labels, err.Error()))
+				break
+			}
+		}
+
+		for _, mode := range spec.Modes {
+			if _, err := r.NamespaceAnnotations(namespace, spec, mode); err != nil {
+				errList = append(errList, field.Invalid(f.Child("annotations"), spec.Annotations, err.Error()))
+				break
+			}
+		}
+	}
+
+	f := field.NewPath("spec")
+	for namespace, spec := range r.Spec.Namespaces {
+		validate(f.Child("namespaces").Key(namespace), namespace, spec)
+	}
+
+	// The selector is rendered with a placeholder since it applies to any namespace
+	if r.Spec.NamespaceSelector != nil {
+		validate(f.Child("namespaceSelector"), "namespace", r.Spec.NamespaceSelector.NamespaceSpec())
+	}
+
+	return errList
+}
+
+// validateClaimNamesUnchanged checks that the claim name of a namespace or the namespace selector doesn't change
+// while the namespaces have access. The claims are bound to their persistent volumes, so they can't be renamed.
+func (r *LustreFileSystem) validateClaimNamesUnchanged(old *LustreFileSystem) field.ErrorList {
+	var errList field.ErrorList
+
+	f := field.NewPath("spec")
+	for namespace, spec := range r.Spec.Namespaces {
+		if oldSpec, found := old.Spec.Namespaces[namespace]; found && spec.ClaimName != oldSpec.ClaimName {
+			errList = append(errList, field.Forbidden(f.Child("namespaces").Key(namespace).Child("claimName"), "the claim name can't change while the namespace has access; remove the namespace first"))
+		}
+	}
+
+	if r.Spec.NamespaceSelector != nil && old.Spec.NamespaceSelector != nil && r.Spec.NamespaceSelector.ClaimName != old.Spec.NamespaceSelector.ClaimName {
+		errList = append(errList, field.Forbidden(f.Child("namespaceSelector").Child("claimName"), "the claim name can't change while the selected namespaces have access; remove the namespace selector first"))
+	}
+
+	return errList
+}
+
+// validateUniqueness checks the file system against every other LustreFileSystem in the cluster. Two objects must
+// not describe the same Lustre file system, which is a file system with the same name and an MGS NID in common, and
+// must not have the same or nested mount roots. Objects with the same name in different namespaces are rejected
+// since the names of their persistent volume claims in a namespace granted access by both, and of the persistent
+// volumes created before PersistentVolumeName hashed the namespace of the file system, would be the same.
+func (r *LustreFileSystem) validateUniqueness() field.ErrorList {
+	var errList field.ErrorList
+
+	if c == nil {
+		return nil
+	}
+
+	filesystems := &LustreFileSystemList{}
+	if err := c.List(context.TODO(), filesystems); err != nil {
+		return append(errList, field.InternalError(field.NewPath("spec"), err))
+	}
+
+	mgsNids := normalizedMgsNids(r.Spec.MgsNids)
+	mountRoot := filepath.Clean(r.Spec.MountRoot)
+
+	for i := range filesystems.Items {
+		other := &filesystems.Items[i]
+		if other.Name == r.Name && other.Namespace == r.Namespace {
+			continue
+		}
+
+		key := other.Namespace + "/" + other.Name
+
+		if other.Name == r.Name {
+			errList = append(errList, field.Invalid(field.NewPath("metadata").Child("name"), r.Name,
+				fmt.Sprintf("LustreFileSystem '%s' has the same name, so their persistent volume claim names would conflict", key)))
+		}
+
+		if other.Spec.Name == r.Spec.Name {
+			for nid := range normalizedMgsNids(other.Spec.MgsNids) {
+				if mgsNids[nid] {
+					errList = append(errList, field.Invalid(field.NewPath("spec").Child("mgsNids"), r.Spec.MgsNids,
+						fmt.Sprintf("file system '%s' with MGS NID '%s' is already defined by LustreFileSystem '%s'", r.Spec.Name, nid, key)))
+					break
+				}
+			}
+		}
+
+		otherMountRoot := filepath.Clean(other.Spec.MountRoot)
+		if isSubpath(mountRoot, otherMountRoot) || isSubpath(otherMountRoot, mountRoot) {
+			errList = append(errList, field.Invalid(field.NewPath("spec").Child("mountRoot"), r.Spec.MountRoot,
+				fmt.Sprintf("mount root overlaps mount root '%s' of LustreFileSystem '%s'", other.Spec.MountRoot, key)))
+		}
+	}
+
+	return errList
+}
+
+// validateStorageClassPolicy checks that a storage class managed by the file system isn't used by another
+// LustreFileSystem, since it's deleted with the file system that manages it
+func (r *LustreFileSystem) validateStorageClassPolicy() field.ErrorList {
+	var errList field.ErrorList
+
+	if c == nil || len(r.Spec.StorageClassName) == 0 {
+		return nil
+	}
+
+	filesystems := &LustreFileSystemList{}
+	if err := c.List(context.TODO(), filesystems); err != nil {
+		return append(errList, field.InternalError(field.NewPath("spec"), err))
+	}
+
+	for i := range filesystems.Items {
+		other := &filesystems.Items[i]
+		if other.Name == r.Name && other.Namespace == r.Namespace {
+			continue
+		}
+
+		if other.Spec.StorageClassName != r.Spec.StorageClassName {
+			continue
+		}
+
+		key := other.Namespace + "/" + other.Name
+		if r.Spec.StorageClassPolicy == StorageClassManaged {
+			errList = append(errList, field.Invalid(field.NewPath("spec").Child("storageClassPolicy"), r.Spec.StorageClassPolicy,
+				fmt.Sprintf("storage class '%s' is also used by LustreFileSystem '%s', so it can't be managed by this file system", r.Spec.StorageClassName, key)))
+		} else if other.Spec.StorageClassPolicy == StorageClassManaged {
+			errList = append(errList, field.Invalid(field.NewPath("spec").Child("storageClassName"), r.Spec.StorageClassName,
+				fmt.Sprintf("storage class is managed by LustreFileSystem '%s'", key)))
+		}
+	}
+
+	return errList
+}
+
+// normalizedMgsNids returns the set of NIDs in a list of MGS NIDs. Valid NIDs are normalized so the same NID
+// written differently, such as with a network number of zero, is only present once.
+func normalizedMgsNids(mgsNids string) map[string]bool {
+	nids := map[string]bool{}
+	for _, node := range SplitNids(mgsNids) {
+		for _, nid := range node {
+			if len(nid) == 0 {
+				continue
+			}
+
+			if parsed, err := ParseNid(nid); err == nil {
+				nid = parsed.String()
+			}
+
+			nids[nid] = true
+		}
+	}
+
+	return nids
+}
+
+// isSubpath returns true if the clean path is the same as, or is within, the clean parent path
+func isSubpath(path string, parent string) bool {
+	if path == parent || parent == "/" {
+		return true
+	}
+
+	return strings.HasPrefix(path, parent+"/")
 }
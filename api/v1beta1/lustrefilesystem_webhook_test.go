@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2024 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -21,12 +21,22 @@ package v1beta1
 
 import (
 	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 // These tests are written in BDD-style using Ginkgo framework. Refer to
@@ -93,6 +103,162 @@ var _ = Describe("LustreFileSystem Webhook", func() {
 			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
 		})
 
+		It("should fill in the MGS nodes from the nid list", func() {
+			createdFS.Spec.MgsNids = "10.0.0.1@tcp,10.1.0.1@o2ib1:10.0.0.2@tcp"
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(createdFS), retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.MgsNodes).To(Equal([]LustreFileSystemMgsNode{
+				{Nids: []string{"10.0.0.1@tcp", "10.1.0.1@o2ib1"}},
+				{Nids: []string{"10.0.0.2@tcp"}},
+			}))
+		})
+
+		It("should fill in the nid list from the MGS nodes", func() {
+			createdFS.Spec.MgsNids = ""
+			createdFS.Spec.MgsNodes = []LustreFileSystemMgsNode{
+				{Nids: []string{"10.0.0.1@tcp"}},
+				{Nids: []string{"[fe80::2]@tcp", "27@gni"}},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(createdFS), retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.MgsNids).To(Equal("10.0.0.1@tcp:[fe80::2]@tcp,27@gni"))
+		})
+
+		It("should default the CSI driver", func() {
+			By("creating an object")
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.CSIDriver).To(Equal(GetDefaults().CSIDriver))
+			Expect(retrievedFS.Spec.StorageClassName).To(Equal(GetDefaults().StorageClassName))
+		})
+
+		It("should replace the default storage class with the operator's default", func() {
+			defaults := GetDefaults()
+			DeferCleanup(SetDefaults, defaults)
+			defaults.StorageClassName = "configured-storage-class"
+			SetDefaults(defaults)
+
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.StorageClassName).To(Equal("configured-storage-class"))
+		})
+
+		It("should normalize the nid list and the mount root", func() {
+			createdFS.Spec.MgsNids = " 10.0.0.1@tcp , 10.1.0.1@o2ib1 :10.0.0.2@tcp:"
+			createdFS.Spec.MountRoot = "/lus/webhook//test/"
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.MgsNids).To(Equal("10.0.0.1@tcp,10.1.0.1@o2ib1:10.0.0.2@tcp"))
+			Expect(retrievedFS.Spec.MountRoot).To(Equal("/lus/webhook/test"))
+		})
+
+		It("should default the modes and mount options", func() {
+			defaults := GetDefaults()
+			DeferCleanup(SetDefaults, defaults)
+
+			withModes := defaults
+			withModes.Modes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}
+			withModes.MountOptions = []string{"flock"}
+			SetDefaults(withModes)
+
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default":     {},
+				"kube-system": {Modes: []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany}},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.Namespaces["default"].Modes).To(ConsistOf(corev1.ReadWriteMany))
+			Expect(retrievedFS.Spec.Namespaces["kube-system"].Modes).To(ConsistOf(corev1.ReadOnlyMany))
+			Expect(retrievedFS.Spec.MountOptions).To(Equal([]string{"flock"}))
+		})
+
+		It("should create an object successfully, with mount options", func() {
+			By("creating an object")
+			createdFS.Spec.MountOptions = []string{"flock", "noatime", "user_xattr", "lazystatfs"}
+			createdFS.Spec.VolumeAttributes = map[string]string{"networks": "tcp0"}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+		})
+
+		It("should create an object successfully, with subdirectories", func() {
+			By("creating an object")
+			createdFS.Spec.Subdirectory = "projects/{{.Namespace}}"
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {Subdirectory: "shared/{{.FileSystemName}}"},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+		})
+
+		It("should allow a change to the MGS NIDs with the rollover policy", func() {
+			By("creating an object")
+			createdFS.Spec.MgsNidsUpdatePolicy = MgsNidsUpdateRollover
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+
+			By("updating the object")
+			retrievedFS.Spec.MgsNids = "127.0.0.2@tcp:127.0.0.3@tcp"
+			retrievedFS.Spec.MgsNodes = nil
+			Expect(k8sClient.Update(context.TODO(), retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.MgsNodes).To(HaveLen(2))
+		})
+
+		It("should allow an update to an object whose nid list predates the normalization", func() {
+			old := createdFS.DeepCopy()
+			old.Spec.MgsNids = "10.0.0.1@tcp, 10.0.0.2@tcp"
+			old.Spec.MgsNodes = []LustreFileSystemMgsNode{{Nids: []string{"10.0.0.1@tcp ", " 10.0.0.2@tcp"}}}
+			old.Spec.CSIDriver = GetDefaults().CSIDriver
+			old.Spec.StorageClassName = GetDefaults().StorageClassName
+
+			By("adding a finalizer the way the controller does")
+			updated := old.DeepCopy()
+			updated.SetFinalizers([]string{"test/finalizer"})
+			updated.Default()
+			Expect(updated.Spec.MgsNids).To(Equal("10.0.0.1@tcp,10.0.0.2@tcp"))
+
+			_, err := updated.ValidateUpdate(old)
+			Expect(err).NotTo(HaveOccurred())
+			createdFS = nil
+		})
+
+		It("should warn about the pods that hold the deletion", func() {
+			createdFS.Status.Namespaces = map[string]LustreFileSystemNamespaceStatus{
+				"default": {
+					Modes: map[corev1.PersistentVolumeAccessMode]LustreFileSystemNamespaceAccessStatus{
+						corev1.ReadWriteMany: {PersistentVolumeClaimRef: &corev1.LocalObjectReference{Name: "webhook-claim"}},
+					},
+				},
+			}
+
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "webhook-pod", Namespace: "default"},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+					Volumes: []corev1.Volume{{
+						Name: "lustre",
+						VolumeSource: corev1.VolumeSource{
+							PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "webhook-claim"},
+						},
+					}},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), pod)).To(Succeed())
+			DeferCleanup(func() {
+				Expect(k8sClient.Delete(context.TODO(), pod, client.GracePeriodSeconds(0))).To(Succeed())
+			})
+
+			Eventually(func() admission.Warnings {
+				warnings, err := createdFS.ValidateDelete()
+				Expect(err).NotTo(HaveOccurred())
+				return warnings
+			}).Should(ConsistOf(ContainSubstring("default/webhook-pod")))
+
+			By("forcing the deletion")
+			createdFS.SetAnnotations(map[string]string{ForceDeleteAnnotation: "true"})
+			Expect(createdFS.ValidateDelete()).To(BeEmpty())
+			createdFS = nil
+		})
+
 		It("should allow an update to the metadata", func() {
 			By("creating an object")
 			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
@@ -139,12 +305,319 @@ var _ = Describe("LustreFileSystem Webhook", func() {
 			createdFS = nil
 		})
 
+		It("should report every invalid nid in a nid list", func() {
+			createdFS.Spec.MgsNids = "10.0.0.1@tcp0,foo@bar:27@gni,10.0.0.300@o2ib1:[fe80::1]@tcp"
+			err := k8sClient.Create(context.TODO(), createdFS)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.mgsNids[1]"))
+			Expect(err.Error()).To(ContainSubstring("spec.mgsNids[3]"))
+			Expect(err.Error()).NotTo(ContainSubstring("spec.mgsNids[4]"))
+			createdFS = nil
+		})
+
+		It("should fail with MGS nodes that don't match the nid list", func() {
+			createdFS.Spec.MgsNodes = []LustreFileSystemMgsNode{
+				{Nids: []string{"127.0.0.2@tcp"}},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with an invalid nid in an MGS node", func() {
+			createdFS.Spec.MgsNids = ""
+			createdFS.Spec.MgsNodes = []LustreFileSystemMgsNode{
+				{Nids: []string{"127.0.0.1@tcp"}},
+				{Nids: []string{"127.0.0.2"}},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
 		It("should fail with an invalid 'mountRoot' attribute", func() {
 			createdFS.Spec.MountRoot = "mangled\npath\r"
 			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
 			createdFS = nil
 		})
 
+		It("should fail with an invalid 'namespaceSelector' attribute", func() {
+			createdFS.Spec.NamespaceSelector = &LustreFileSystemNamespaceSelector{
+				LabelSelector: metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "lustre.access/home", Operator: metav1.LabelSelectorOpIn},
+					},
+				},
+				Modes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with a CSI driver that is not installed", func() {
+			createdFS.Spec.CSIDriver = "not-installed.csi.example.com"
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail to change the CSI driver", func() {
+			By("creating an object")
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+
+			By("updating the object")
+			retrievedFS.Spec.CSIDriver = "other.csi.example.com"
+			Expect(k8sClient.Update(context.TODO(), retrievedFS)).ToNot(Succeed())
+		})
+
+		It("should fail without a storage class when the operator has no default", func() {
+			defaults := GetDefaults()
+			DeferCleanup(SetDefaults, defaults)
+			defaults.StorageClassName = ""
+			SetDefaults(defaults)
+
+			// The CRD fills in its default storage class when the field is missing, so only an explicitly empty
+			// storage class gets to the webhook
+			createdFS.Default()
+			_, err := createdFS.ValidateCreate()
+			Expect(err).To(MatchError(ContainSubstring("no storage class specified")))
+			createdFS = nil
+		})
+
+		It("should fail with an unsafe mount option", func() {
+			createdFS.Spec.MountOptions = []string{"flock", "suid"}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with a combined mount option entry", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {MountOptions: []string{"flock,noatime"}},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail to add an unsafe mount option on update", func() {
+			By("creating an object")
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+
+			By("updating the object")
+			retrievedFS.Spec.MountOptions = []string{"localflock"}
+			Expect(k8sClient.Update(context.TODO(), retrievedFS)).ToNot(Succeed())
+		})
+
+		It("should fail with the ReadWriteOncePod access mode", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {Modes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod}},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with both an expiry time and a TTL", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {
+					ExpiresAt: &metav1.Time{Time: time.Now().Add(time.Hour)},
+					TTL:       &metav1.Duration{Duration: time.Hour},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with a quota on a namespace without a subdirectory", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {
+					Quota: &LustreFileSystemNamespaceQuota{BlockLimit: resource.NewQuantity(1<<30, resource.BinarySI)},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with a negative quota", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {
+					Subdirectory: "projects/{{.Namespace}}",
+					Quota:        &LustreFileSystemNamespaceQuota{BlockLimit: resource.NewQuantity(-1, resource.BinarySI)},
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with an unsupported access mode in the access policy", func() {
+			createdFS.Spec.AccessPolicy = &LustreFileSystemAccessPolicy{
+				Modes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOncePod},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with an invalid namespace in the access policy", func() {
+			createdFS.Spec.AccessPolicy = &LustreFileSystemAccessPolicy{
+				Namespaces: []string{"Not_A_Namespace"},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with a duplicate access mode in the namespace selector", func() {
+			createdFS.Spec.NamespaceSelector = &LustreFileSystemNamespaceSelector{
+				Modes: []corev1.PersistentVolumeAccessMode{corev1.ReadOnlyMany, corev1.ReadOnlyMany},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with a subdirectory outside the file system", func() {
+			createdFS.Spec.Subdirectory = "../{{.Namespace}}"
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail with an invalid subdirectory template", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {Subdirectory: "{{.Namespace"},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).NotTo(Succeed())
+			createdFS = nil
+		})
+
+		It("should fail to change the MGS NIDs without the rollover policy", func() {
+			By("creating an object")
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+			Expect(retrievedFS.Spec.MgsNidsUpdatePolicy).To(Equal(MgsNidsUpdateForbid))
+
+			By("updating the object")
+			retrievedFS.Spec.MgsNids = "127.0.0.2@tcp"
+			retrievedFS.Spec.MgsNodes = nil
+			Expect(k8sClient.Update(context.TODO(), retrievedFS)).ToNot(Succeed())
+		})
+
+		It("should fail to change the MGS NIDs to an invalid nid with the rollover policy", func() {
+			By("creating an object")
+			createdFS.Spec.MgsNidsUpdatePolicy = MgsNidsUpdateRollover
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+
+			By("updating the object")
+			retrievedFS.Spec.MgsNids = "127.0.0.2"
+			retrievedFS.Spec.MgsNodes = nil
+			Expect(k8sClient.Update(context.TODO(), retrievedFS)).ToNot(Succeed())
+		})
+
+		Context("with an existing file system", func() {
+			var existingFS *LustreFileSystem
+
+			BeforeEach(func() {
+				existingFS = &LustreFileSystem{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "webhook-existing",
+						Namespace: key.Namespace,
+					},
+					Spec: LustreFileSystemSpec{
+						Name:      "foo",
+						MgsNids:   "127.0.0.1@tcp0,10.0.0.1@o2ib",
+						MountRoot: "/lus/existing",
+					},
+				}
+				Expect(k8sClient.Create(context.TODO(), existingFS)).To(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(k8sClient.Delete(context.TODO(), existingFS)).To(Succeed())
+				Eventually(func() error {
+					return k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(existingFS), existingFS)
+				}).ShouldNot(Succeed())
+			})
+
+			It("should fail with the same file system and MGS", func() {
+				err := k8sClient.Create(context.TODO(), createdFS)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("default/webhook-existing"))
+				createdFS = nil
+			})
+
+			It("should fail with a nested mount root", func() {
+				createdFS.Spec.Name = "bar"
+				createdFS.Spec.MountRoot = "/lus/existing/bar"
+				err := k8sClient.Create(context.TODO(), createdFS)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("default/webhook-existing"))
+				createdFS = nil
+			})
+
+			It("should fail with the same name in another namespace", func() {
+				createdFS.Name = existingFS.Name
+				createdFS.Namespace = "kube-system"
+				createdFS.Spec.Name = "bar"
+				err := k8sClient.Create(context.TODO(), createdFS)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("persistent volume claim names would conflict"))
+				createdFS = nil
+			})
+
+			It("should create a different file system on the same MGS", func() {
+				createdFS.Spec.Name = "bar"
+				Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			})
+
+			It("should fail to manage a storage class used by another file system", func() {
+				createdFS.Spec.Name = "bar"
+				createdFS.Spec.StorageClassPolicy = StorageClassManaged
+				err := k8sClient.Create(context.TODO(), createdFS)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("default/webhook-existing"))
+				createdFS = nil
+			})
+		})
+
+		It("should fail with the same claim name for several modes", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {
+					Modes:     []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany, corev1.ReadOnlyMany},
+					ClaimName: "home",
+				},
+			}
+			err := k8sClient.Create(context.TODO(), createdFS)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.namespaces[default].claimName"))
+			createdFS = nil
+		})
+
+		It("should fail with an owner label in the namespace labels", func() {
+			createdFS.Spec.NamespaceSelector = &LustreFileSystemNamespaceSelector{
+				Modes:  []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+				Labels: map[string]string{OwnerNameLabel: "{{.Name}}"},
+			}
+			err := k8sClient.Create(context.TODO(), createdFS)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("spec.namespaceSelector.labels"))
+			createdFS = nil
+		})
+
+		It("should fail to change the claim name of a namespace", func() {
+			createdFS.Spec.Namespaces = map[string]LustreFileSystemNamespaceSpec{
+				"default": {
+					Modes:     []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany, corev1.ReadOnlyMany},
+					ClaimName: "home-{{.Mode}}",
+				},
+			}
+			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
+			Expect(k8sClient.Get(context.TODO(), key, retrievedFS)).To(Succeed())
+
+			spec := retrievedFS.Spec.Namespaces["default"]
+			spec.ClaimName = "scratch-{{.Mode}}"
+			retrievedFS.Spec.Namespaces["default"] = spec
+			Expect(k8sClient.Update(context.TODO(), retrievedFS)).ToNot(Succeed())
+		})
+
 		It("should fail to update the spec", func() {
 			By("creating an object")
 			Expect(k8sClient.Create(context.TODO(), createdFS)).To(Succeed())
@@ -158,3 +631,180 @@ var _ = Describe("LustreFileSystem Webhook", func() {
 		})
 	})
 })
+
+var _ = Describe("LustreFileSystem Subdirectory", func() {
+	fs := &LustreFileSystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "lustre"},
+		Spec: LustreFileSystemSpec{
+			Name:         "foo",
+			MgsNids:      "127.0.0.1@tcp",
+			Subdirectory: "projects/{{.Namespace}}",
+		},
+	}
+
+	It("renders the default subdirectory", func() {
+		subdirectory, err := fs.NamespaceSubdirectory("tenant", LustreFileSystemNamespaceSpec{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subdirectory).To(Equal("projects/tenant"))
+		Expect(fs.ExportPath(subdirectory)).To(Equal("127.0.0.1@tcp:/foo/projects/tenant"))
+	})
+
+	It("renders the claim name, labels and annotations of a namespace", func() {
+		spec := LustreFileSystemNamespaceSpec{
+			ClaimName:   "{{.FileSystemName}}-{{.Mode}}",
+			Labels:      map[string]string{"example.com/namespace": "{{.Namespace}}"},
+			Annotations: map[string]string{"example.com/mode": "{{.Mode}}"},
+		}
+
+		claimName, err := fs.NamespaceClaimName("tenant", spec, corev1.ReadOnlyMany)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimName).To(Equal("foo-readonlymany"))
+
+		Expect(fs.NamespaceLabels("tenant", spec, corev1.ReadOnlyMany)).To(Equal(map[string]string{"example.com/namespace": "tenant"}))
+		Expect(fs.NamespaceAnnotations("tenant", spec, corev1.ReadOnlyMany)).To(Equal(map[string]string{"example.com/mode": "readonlymany"}))
+
+		claimName, err = fs.NamespaceClaimName("tenant", LustreFileSystemNamespaceSpec{}, corev1.ReadOnlyMany)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimName).To(Equal(fs.PersistentVolumeClaimName("tenant", corev1.ReadOnlyMany)))
+	})
+
+	It("rejects a claim name that isn't an object name", func() {
+		_, err := fs.NamespaceClaimName("tenant", LustreFileSystemNamespaceSpec{ClaimName: "Home_{{.Mode}}"}, corev1.ReadWriteMany)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects an absolute subdirectory", func() {
+		_, err := fs.NamespaceSubdirectory("tenant", LustreFileSystemNamespaceSpec{Subdirectory: "/{{.Name}}"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("renders the namespace subdirectory", func() {
+		subdirectory, err := fs.NamespaceSubdirectory("tenant", LustreFileSystemNamespaceSpec{Subdirectory: "{{.Name}}//{{.FileSystemName}}/"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subdirectory).To(Equal("lustre/foo"))
+	})
+
+	It("exports the root when the subdirectory is the root", func() {
+		subdirectory, err := fs.NamespaceSubdirectory("tenant", LustreFileSystemNamespaceSpec{Subdirectory: "./"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(subdirectory).To(BeEmpty())
+		Expect(fs.ExportPath(subdirectory)).To(Equal("127.0.0.1@tcp:/foo"))
+	})
+})
+
+var _ = Describe("LustreFileSystem Names", func() {
+	newFS := func(name string) *LustreFileSystem {
+		return &LustreFileSystem{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	It("gives distinct persistent volume names to accesses with the same readable prefix", func() {
+		Expect(newFS("a-b").PersistentVolumeName("c", corev1.ReadWriteMany)).
+			ToNot(Equal(newFS("a").PersistentVolumeName("b-c", corev1.ReadWriteMany)))
+	})
+
+	It("keeps the readable claim name when it fits", func() {
+		Expect(newFS("lustre").PersistentVolumeClaimName("tenant", corev1.ReadWriteMany)).To(Equal("lustre-tenant-readwritemany-pvc"))
+		Expect(newFS("lustre").LegacyPersistentVolumeName("tenant", corev1.ReadWriteMany)).To(Equal("lustre-tenant-readwritemany-pv"))
+	})
+
+	It("bounds the names of long namespaces", func() {
+		fs := newFS(strings.Repeat("f", 200))
+		namespace := strings.Repeat("n", 63)
+
+		for _, name := range []string{
+			fs.PersistentVolumeName(namespace, corev1.ReadWriteMany),
+			fs.PersistentVolumeRolloverName(namespace, corev1.ReadWriteMany, "127.0.0.1@tcp:/foo"),
+			fs.PersistentVolumeClaimName(namespace, corev1.ReadWriteMany),
+		} {
+			Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty(), name)
+		}
+
+		Expect(fs.PersistentVolumeClaimName(namespace, corev1.ReadWriteMany)).
+			ToNot(Equal(fs.PersistentVolumeClaimName(namespace, corev1.ReadWriteOnce)))
+	})
+})
+
+var _ = Describe("LustreFileSystem Namespace Access States", func() {
+
+	It("lists every namespace access state", func() {
+		// The states are read from the source, so a new state that's missing from the list fails the test
+		file, err := parser.ParseFile(token.NewFileSet(), "lustrefilesystem_types.go", nil, 0)
+		Expect(err).NotTo(HaveOccurred())
+
+		states := []NamespaceAccessState{}
+		ast.Inspect(file, func(node ast.Node) bool {
+			spec, ok := node.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+
+			if ident, ok := spec.Type.(*ast.Ident); ok && ident.Name == "NamespaceAccessState" {
+				for _, value := range spec.Values {
+					state, err := strconv.Unquote(value.(*ast.BasicLit).Value)
+					Expect(err).NotTo(HaveOccurred())
+					states = append(states, NamespaceAccessState(state))
+				}
+			}
+
+			return false
+		})
+
+		Expect(states).NotTo(BeEmpty())
+		Expect(NamespaceAccessStates()).To(ConsistOf(states))
+	})
+})
+
+var _ = Describe("LustreFileSystem Nid", func() {
+
+	It("parses IP and numeric nids", func() {
+		for s, expected := range map[string]Nid{
+			"10.0.0.1@tcp":        {Address: "10.0.0.1", NetworkType: "tcp"},
+			"10.0.0.1@tcp0":       {Address: "10.0.0.1", NetworkType: "tcp"},
+			"10.0.0.1@o2ib1":      {Address: "10.0.0.1", NetworkType: "o2ib", NetworkNumber: 1},
+			"[fe80::1]@tcp2":      {Address: "fe80::1", NetworkType: "tcp", NetworkNumber: 2},
+			"fe80::1@tcp":         {Address: "fe80::1", NetworkType: "tcp"},
+			"mgs.example.com@tcp": {Address: "mgs.example.com", NetworkType: "tcp"},
+			"27@gni":              {Address: "27", NetworkType: "gni"},
+			"0x1f@kfi3":           {Address: "0x1f", NetworkType: "kfi", NetworkNumber: 3},
+			"12@ptl4":             {Address: "12", NetworkType: "ptl4"},
+			"12@ptl41":            {Address: "12", NetworkType: "ptl4", NetworkNumber: 1},
+			"27@gnipr2":           {Address: "27", NetworkType: "gnipr", NetworkNumber: 2},
+		} {
+			nid, err := ParseNid(s)
+			Expect(err).NotTo(HaveOccurred(), s)
+			Expect(nid).To(Equal(expected), s)
+		}
+	})
+
+	It("rejects invalid nids", func() {
+		for _, s := range []string{
+			"10.0.0.1",
+			"10.0.0.1@",
+			"@tcp",
+			"10.0.0.1@foo",
+			"10.0.0.1@tcp99999999999",
+			"10.0.0.300@tcp",
+			"host_name@tcp",
+			"[10.0.0.1]@tcp",
+			"fe80:::1@tcp",
+			"node@gni",
+			"12@ptl",
+		} {
+			_, err := ParseNid(s)
+			Expect(err).To(HaveOccurred(), s)
+		}
+	})
+
+	It("formats nids", func() {
+		Expect(Nid{Address: "10.0.0.1", NetworkType: "tcp"}.String()).To(Equal("10.0.0.1@tcp"))
+		Expect(Nid{Address: "fe80::1", NetworkType: "o2ib", NetworkNumber: 1}.String()).To(Equal("[fe80::1]@o2ib1"))
+	})
+
+	It("splits failover nodes and multi-nid nodes", func() {
+		Expect(SplitNids("10.0.0.1@tcp,10.0.1.1@o2ib:fe80::2@tcp:27@gni")).To(Equal([][]string{
+			{"10.0.0.1@tcp", "10.0.1.1@o2ib"},
+			{"fe80::2@tcp"},
+			{"27@gni"},
+		}))
+	})
+})
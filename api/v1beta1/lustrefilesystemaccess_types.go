@@ -0,0 +1,163 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"github.com/DataWorkflowServices/dws/utils/updater"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// LustreFileSystemReference refers to a LustreFileSystem by name and namespace
+type LustreFileSystemReference struct {
+	// Name is the name of the LustreFileSystem
+	// +kubebuilder:validation:MinLength:=1
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the LustreFileSystem
+	// +kubebuilder:validation:MinLength:=1
+	Namespace string `json:"namespace"`
+}
+
+// LustreFileSystemAccessSpec defines the access requested to a Lustre file system
+type LustreFileSystemAccessSpec struct {
+	// FileSystemRef refers to the LustreFileSystem the namespace requests access to
+	FileSystemRef LustreFileSystemReference `json:"fileSystemRef"`
+
+	// Mode is the persistent volume access mode requested. A ReadOnlyMany access is mounted read only.
+	Mode corev1.PersistentVolumeAccessMode `json:"mode"`
+}
+
+// LustreFileSystemAccessStatus defines the observed status of the access request
+type LustreFileSystemAccessStatus struct {
+	// State represents the current state of the access request
+	State LustreFileSystemAccessState `json:"state,omitempty"`
+
+	// Message is a human readable description of the current state
+	Message string `json:"message,omitempty"`
+
+	// PersistentVolumeClaimRef holds a reference to the persistent volume claim in the namespace of the request
+	// once the access is granted
+	PersistentVolumeClaimRef *corev1.LocalObjectReference `json:"persistentVolumeClaimRef,omitempty"`
+
+	// ObservedGeneration is the generation of the specification that was last reconciled.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions represent the latest available observations of the access request. An administrator approves
+	// or denies a request the access policy doesn't approve automatically by setting the Approved condition with
+	// the status subresource, which the lustrefilesystemaccess-approver-role allows. The namespace that owns the
+	// request can't write the status, so it can't approve its own request.
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+type LustreFileSystemAccessState string
+
+const (
+	// AccessRequestPending - used to indicate the request is waiting to be approved
+	AccessRequestPending LustreFileSystemAccessState = "Pending"
+
+	// AccessRequestDenied - used to indicate the request is not allowed by the access policy or was denied
+	AccessRequestDenied LustreFileSystemAccessState = "Denied"
+
+	// AccessRequestApproved - used to indicate the request is approved and the claim is not yet ready
+	AccessRequestApproved LustreFileSystemAccessState = "Approved"
+
+	// AccessRequestReady - used to indicate the claim is ready for use
+	AccessRequestReady LustreFileSystemAccessState = "Ready"
+
+	// AccessRequestConflict - used to indicate the request is approved and the namespace is already granted access
+	// with a named claim for another mode, which takes precedence over the request
+	AccessRequestConflict LustreFileSystemAccessState = "Conflict"
+
+	// AccessRequestFileSystemNotFound - used to indicate the LustreFileSystem does not exist
+	AccessRequestFileSystemNotFound LustreFileSystemAccessState = "FileSystemNotFound"
+)
+
+const (
+	// ConditionApproved is true when the request is approved and false when it is denied
+	ConditionApproved = "Approved"
+)
+
+const (
+	// ConditionReasonAutoApproved - used when the access policy approved the request
+	ConditionReasonAutoApproved = "AutoApproved"
+
+	// ConditionReasonNotAllowed - used when the access policy doesn't allow the request
+	ConditionReasonNotAllowed = "NotAllowed"
+
+	// ConditionReasonFileSystemNotFound - used when the LustreFileSystem does not exist
+	ConditionReasonFileSystemNotFound = "FileSystemNotFound"
+)
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="FILESYSTEM",type="string",JSONPath=".spec.fileSystemRef.name",description="LustreFileSystem requested"
+//+kubebuilder:printcolumn:name="MODE",type="string",JSONPath=".spec.mode",description="Access mode requested"
+//+kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.state",description="State of the request"
+//+kubebuilder:printcolumn:name="CLAIM",type="string",JSONPath=".status.persistentVolumeClaimRef.name",description="Persistent volume claim granted"
+//+kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+
+// LustreFileSystemAccess is the Schema for the lustrefilesystemaccesses API. A namespace creates one to request
+// access to a LustreFileSystem; the persistent volume claim is created in the namespace once the request is
+// approved and deleted when the request is deleted. The request is approved through the Approved condition of
+// its status, which is set by the operator or by an administrator, never by the namespace.
+type LustreFileSystemAccess struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LustreFileSystemAccessSpec   `json:"spec,omitempty"`
+	Status LustreFileSystemAccessStatus `json:"status,omitempty"`
+}
+
+// Approved returns true when the Approved condition of the request is true
+func (a *LustreFileSystemAccess) Approved() bool {
+	return meta.IsStatusConditionTrue(a.Status.Conditions, ConditionApproved)
+}
+
+func (a *LustreFileSystemAccess) GetStatus() updater.Status[*LustreFileSystemAccessStatus] {
+	return &a.Status
+}
+
+//+kubebuilder:object:root=true
+
+// LustreFileSystemAccessList contains a list of LustreFileSystemAccess
+type LustreFileSystemAccessList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LustreFileSystemAccess `json:"items"`
+}
+
+func (list *LustreFileSystemAccessList) GetObjectList() []client.Object {
+	objectList := make([]client.Object, len(list.Items))
+
+	for i := range list.Items {
+		objectList[i] = &list.Items[i]
+	}
+
+	return objectList
+}
+
+func init() {
+	SchemeBuilder.Register(&LustreFileSystemAccess{}, &LustreFileSystemAccessList{})
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var lustrefilesystemaccesslog = logf.Log.WithName("lustrefilesystemaccess-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *LustreFileSystemAccess) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-lus-cray-hpe-com-v1beta1-lustrefilesystemaccess,mutating=false,failurePolicy=fail,sideEffects=None,groups=lus.cray.hpe.com,resources=lustrefilesystemaccesses,verbs=create;update,versions=v1beta1,name=vlustrefilesystemaccess.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Validator = &LustreFileSystemAccess{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *LustreFileSystemAccess) ValidateCreate() (admission.Warnings, error) {
+	lustrefilesystemaccesslog.Info("validate create", "namespace", r.Namespace, "name", r.Name)
+
+	var errList field.ErrorList
+
+	f := field.NewPath("spec")
+	if len(r.Spec.FileSystemRef.Name) == 0 {
+		errList = append(errList, field.Required(f.Child("fileSystemRef").Child("name"), "the LustreFileSystem name is required"))
+	}
+
+	if len(r.Spec.FileSystemRef.Namespace) == 0 {
+		errList = append(errList, field.Required(f.Child("fileSystemRef").Child("namespace"), "the LustreFileSystem namespace is required"))
+	}
+
+	if err := validateMode(f.Child("mode"), r.Spec.Mode); err != nil {
+		errList = append(errList, err)
+	}
+
+	return nil, r.invalid(errList)
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *LustreFileSystemAccess) ValidateUpdate(obj runtime.Object) (admission.Warnings, error) {
+	lustrefilesystemaccesslog.Info("validate update", "namespace", r.Namespace, "name", r.Name)
+
+	old := obj.(*LustreFileSystemAccess)
+
+	// A different file system or mode is a different request, which must be approved again
+	var errList field.ErrorList
+	if r.Spec != old.Spec {
+		errList = append(errList, field.Forbidden(field.NewPath("spec"), "field is immutable"))
+	}
+
+	return nil, r.invalid(errList)
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *LustreFileSystemAccess) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (r *LustreFileSystemAccess) invalid(errList field.ErrorList) error {
+	if len(errList) == 0 {
+		return nil
+	}
+
+	return errors.NewInvalid(
+		schema.GroupKind{Group: "", Kind: "LustreFileSystemAccess"},
+		r.Name,
+		errList,
+	)
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = Describe("LustreFileSystemAccess Webhook", func() {
+
+	var createdAccess *LustreFileSystemAccess
+
+	BeforeEach(func() {
+		createdAccess = &LustreFileSystemAccess{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "webhook-access-test",
+				Namespace: "default",
+			},
+			Spec: LustreFileSystemAccessSpec{
+				FileSystemRef: LustreFileSystemReference{Name: "webhook-test", Namespace: "default"},
+				Mode:          corev1.ReadWriteMany,
+			},
+		}
+	})
+
+	AfterEach(func() {
+		if createdAccess != nil {
+			Expect(k8sClient.Delete(context.TODO(), createdAccess)).To(Succeed())
+		}
+	})
+
+	It("should create a request successfully", func() {
+		Expect(k8sClient.Create(context.TODO(), createdAccess)).To(Succeed())
+	})
+
+	It("should fail with the ReadWriteOncePod access mode", func() {
+		createdAccess.Spec.Mode = corev1.ReadWriteOncePod
+		Expect(k8sClient.Create(context.TODO(), createdAccess)).NotTo(Succeed())
+		createdAccess = nil
+	})
+
+	It("should fail to change the requested mode", func() {
+		Expect(k8sClient.Create(context.TODO(), createdAccess)).To(Succeed())
+
+		retrievedAccess := &LustreFileSystemAccess{}
+		Expect(k8sClient.Get(context.TODO(), client.ObjectKeyFromObject(createdAccess), retrievedAccess)).To(Succeed())
+		retrievedAccess.Spec.Mode = corev1.ReadOnlyMany
+		Expect(k8sClient.Update(context.TODO(), retrievedAccess)).NotTo(Succeed())
+	})
+})
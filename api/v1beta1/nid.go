@@ -0,0 +1,204 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1beta1
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Nid is a parsed LNet network identifier of the form ADDRESS@NETWORK, where NETWORK is a network type
+// followed by an optional network number, e.g. 10.0.0.1@tcp0 or 27@gni.
+// +kubebuilder:object:generate=false
+type Nid struct {
+	// Address is the address of the node on the network. It is an IPv4 or IPv6 address or a host name
+	// for IP based networks, and a number for the other networks.
+	Address string
+
+	// NetworkType is the LNet network type, e.g. tcp or o2ib
+	NetworkType string
+
+	// NetworkNumber is the number of the network of the given type
+	NetworkNumber uint32
+}
+
+// String returns the NID as ADDRESS@NETWORK, omitting a network number of zero
+func (n Nid) String() string {
+	address := n.Address
+	if strings.Contains(address, ":") {
+		address = "[" + address + "]"
+	}
+
+	if n.NetworkNumber == 0 {
+		return address + "@" + n.NetworkType
+	}
+
+	return address + "@" + n.NetworkType + strconv.FormatUint(uint64(n.NetworkNumber), 10)
+}
+
+// nidNetworkTypes are the LNet network types, and whether the network uses IP addresses
+var nidNetworkTypes = map[string]bool{
+	"tcp":   true,
+	"o2ib":  true,
+	"efa":   true,
+	"kfi":   false,
+	"gni":   false,
+	"gnipr": false,
+	"ptl4":  false,
+	"lo":    false,
+}
+
+// nidNetworkTypesLongestFirst lists the network types longest first, so a network is matched to its longest
+// network type, e.g. gnipr rather than gni. A network type may end in a digit, like ptl4, so the network number
+// can't be told apart from the network type by its digits alone.
+var nidNetworkTypesLongestFirst = func() []string {
+	networkTypes := slices.Collect(maps.Keys(nidNetworkTypes))
+	slices.SortFunc(networkTypes, func(a, b string) int {
+		return cmp.Or(cmp.Compare(len(b), len(a)), cmp.Compare(a, b))
+	})
+
+	return networkTypes
+}()
+
+var nidNetworkRegexp = regexp.MustCompile(`^([a-z]+[a-z0-9]*?)([0-9]*)$`)
+
+var nidNetworkNumberRegexp = regexp.MustCompile(`^[0-9]*$`)
+
+var nidNumericRegexp = regexp.MustCompile(`^[0-9.]+$`)
+
+// ParseNid parses a single NID
+func ParseNid(s string) (Nid, error) {
+	at := strings.LastIndex(s, "@")
+	if at == -1 {
+		return Nid{}, fmt.Errorf("must be of the form ADDRESS@NETWORK")
+	}
+
+	address, network := s[:at], s[at+1:]
+
+	match := nidNetworkRegexp.FindStringSubmatch(network)
+	if match == nil {
+		return Nid{}, fmt.Errorf("invalid network '%s'", network)
+	}
+
+	// The network type is the longest known network type the network starts with, and the rest is the number
+	networkType, networkNumber := "", ""
+	for _, t := range nidNetworkTypesLongestFirst {
+		if rest, found := strings.CutPrefix(network, t); found && nidNetworkNumberRegexp.MatchString(rest) {
+			networkType, networkNumber = t, rest
+			break
+		}
+	}
+
+	if len(networkType) == 0 {
+		return Nid{}, fmt.Errorf("unknown network type '%s'", match[1])
+	}
+
+	ip := nidNetworkTypes[networkType]
+	nid := Nid{NetworkType: networkType}
+	if len(networkNumber) != 0 {
+		number, err := strconv.ParseUint(networkNumber, 10, 32)
+		if err != nil {
+			return Nid{}, fmt.Errorf("invalid network number '%s'", networkNumber)
+		}
+
+		nid.NetworkNumber = uint32(number)
+	}
+
+	if len(address) == 0 {
+		return Nid{}, fmt.Errorf("missing address")
+	}
+
+	if !ip {
+		if _, err := strconv.ParseUint(address, 0, 32); err != nil {
+			return Nid{}, fmt.Errorf("address '%s' must be a number for network type '%s'", address, nid.NetworkType)
+		}
+
+		nid.Address = address
+		return nid, nil
+	}
+
+	// IPv6 addresses may be enclosed in brackets
+	if strings.HasPrefix(address, "[") && strings.HasSuffix(address, "]") {
+		address = address[1 : len(address)-1]
+		if net.ParseIP(address) == nil || !strings.Contains(address, ":") {
+			return Nid{}, fmt.Errorf("invalid IPv6 address '%s'", address)
+		}
+	}
+
+	if ipAddress := net.ParseIP(address); ipAddress != nil {
+		nid.Address = address
+		return nid, nil
+	}
+
+	if strings.Contains(address, ":") {
+		return Nid{}, fmt.Errorf("invalid IPv6 address '%s'", address)
+	}
+
+	// A host name can't be entirely numeric, so this is a malformed IPv4 address
+	if nidNumericRegexp.MatchString(address) {
+		return Nid{}, fmt.Errorf("invalid IPv4 address '%s'", address)
+	}
+
+	if msgs := validation.IsDNS1123Subdomain(strings.ToLower(address)); len(msgs) != 0 {
+		return Nid{}, fmt.Errorf("invalid host name '%s': %s", address, strings.Join(msgs, "; "))
+	}
+
+	nid.Address = address
+	return nid, nil
+}
+
+// SplitNids splits a list of NIDs into the NIDs of each node. A ':' separates failover nodes and a ','
+// separates the NIDs of one node. A ':' is only a separator after the network of a NID, so IPv6 addresses
+// are not split. The NIDs are not validated.
+func SplitNids(s string) [][]string {
+	nodes := [][]string{}
+	node := []string{}
+
+	start, network := 0, false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '@':
+			network = true
+		case ',':
+			node = append(node, s[start:i])
+			start, network = i+1, false
+		case ':':
+			if !network {
+				continue
+			}
+
+			node = append(node, s[start:i])
+			nodes = append(nodes, node)
+			node = []string{}
+			start, network = i+1, false
+		}
+	}
+
+	node = append(node, s[start:])
+	return append(nodes, node)
+}
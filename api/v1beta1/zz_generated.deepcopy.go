@@ -25,9 +25,26 @@ package v1beta1
 
 import (
 	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccessTemplateData) DeepCopyInto(out *AccessTemplateData) {
+	*out = *in
+	out.SubdirectoryTemplateData = in.SubdirectoryTemplateData
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccessTemplateData.
+func (in *AccessTemplateData) DeepCopy() *AccessTemplateData {
+	if in == nil {
+		return nil
+	}
+	out := new(AccessTemplateData)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LustreFileSystem) DeepCopyInto(out *LustreFileSystem) {
 	*out = *in
@@ -55,6 +72,138 @@ func (in *LustreFileSystem) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemAccess) DeepCopyInto(out *LustreFileSystemAccess) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemAccess.
+func (in *LustreFileSystemAccess) DeepCopy() *LustreFileSystemAccess {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemAccess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LustreFileSystemAccess) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemAccessList) DeepCopyInto(out *LustreFileSystemAccessList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LustreFileSystemAccess, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemAccessList.
+func (in *LustreFileSystemAccessList) DeepCopy() *LustreFileSystemAccessList {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemAccessList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LustreFileSystemAccessList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemAccessPolicy) DeepCopyInto(out *LustreFileSystemAccessPolicy) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemAccessPolicy.
+func (in *LustreFileSystemAccessPolicy) DeepCopy() *LustreFileSystemAccessPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemAccessPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemAccessSpec) DeepCopyInto(out *LustreFileSystemAccessSpec) {
+	*out = *in
+	out.FileSystemRef = in.FileSystemRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemAccessSpec.
+func (in *LustreFileSystemAccessSpec) DeepCopy() *LustreFileSystemAccessSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemAccessSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemAccessStatus) DeepCopyInto(out *LustreFileSystemAccessStatus) {
+	*out = *in
+	if in.PersistentVolumeClaimRef != nil {
+		in, out := &in.PersistentVolumeClaimRef, &out.PersistentVolumeClaimRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemAccessStatus.
+func (in *LustreFileSystemAccessStatus) DeepCopy() *LustreFileSystemAccessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemAccessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LustreFileSystemList) DeepCopyInto(out *LustreFileSystemList) {
 	*out = *in
@@ -87,6 +236,26 @@ func (in *LustreFileSystemList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemMgsNode) DeepCopyInto(out *LustreFileSystemMgsNode) {
+	*out = *in
+	if in.Nids != nil {
+		in, out := &in.Nids, &out.Nids
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemMgsNode.
+func (in *LustreFileSystemMgsNode) DeepCopy() *LustreFileSystemMgsNode {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemMgsNode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LustreFileSystemNamespaceAccessStatus) DeepCopyInto(out *LustreFileSystemNamespaceAccessStatus) {
 	*out = *in
@@ -100,6 +269,15 @@ func (in *LustreFileSystemNamespaceAccessStatus) DeepCopyInto(out *LustreFileSys
 		*out = new(v1.LocalObjectReference)
 		**out = **in
 	}
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceAccessStatus.
@@ -112,6 +290,124 @@ func (in *LustreFileSystemNamespaceAccessStatus) DeepCopy() *LustreFileSystemNam
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemNamespaceQuota) DeepCopyInto(out *LustreFileSystemNamespaceQuota) {
+	*out = *in
+	if in.BlockLimit != nil {
+		in, out := &in.BlockLimit, &out.BlockLimit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.InodeLimit != nil {
+		in, out := &in.InodeLimit, &out.InodeLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceQuota.
+func (in *LustreFileSystemNamespaceQuota) DeepCopy() *LustreFileSystemNamespaceQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemNamespaceQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemNamespaceQuotaStatus) DeepCopyInto(out *LustreFileSystemNamespaceQuotaStatus) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(LustreFileSystemNamespaceQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BlockUsage != nil {
+		in, out := &in.BlockUsage, &out.BlockUsage
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LastUpdateTime != nil {
+		in, out := &in.LastUpdateTime, &out.LastUpdateTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceQuotaStatus.
+func (in *LustreFileSystemNamespaceQuotaStatus) DeepCopy() *LustreFileSystemNamespaceQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemNamespaceQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemNamespaceRolloverStatus) DeepCopyInto(out *LustreFileSystemNamespaceRolloverStatus) {
+	*out = *in
+	if in.PendingModes != nil {
+		in, out := &in.PendingModes, &out.PendingModes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceRolloverStatus.
+func (in *LustreFileSystemNamespaceRolloverStatus) DeepCopy() *LustreFileSystemNamespaceRolloverStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemNamespaceRolloverStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemNamespaceSelector) DeepCopyInto(out *LustreFileSystemNamespaceSelector) {
+	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.Modes != nil {
+		in, out := &in.Modes, &out.Modes
+		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceSelector.
+func (in *LustreFileSystemNamespaceSelector) DeepCopy() *LustreFileSystemNamespaceSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemNamespaceSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LustreFileSystemNamespaceSpec) DeepCopyInto(out *LustreFileSystemNamespaceSpec) {
 	*out = *in
@@ -120,6 +416,46 @@ func (in *LustreFileSystemNamespaceSpec) DeepCopyInto(out *LustreFileSystemNames
 		*out = make([]v1.PersistentVolumeAccessMode, len(*in))
 		copy(*out, *in)
 	}
+	if in.MountOptions != nil {
+		in, out := &in.MountOptions, &out.MountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(LustreFileSystemNamespaceQuota)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceSpec.
@@ -142,6 +478,24 @@ func (in *LustreFileSystemNamespaceStatus) DeepCopyInto(out *LustreFileSystemNam
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Rollover != nil {
+		in, out := &in.Rollover, &out.Rollover
+		*out = new(LustreFileSystemNamespaceRolloverStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GrantedAt != nil {
+		in, out := &in.GrantedAt, &out.GrantedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(LustreFileSystemNamespaceQuotaStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemNamespaceStatus.
@@ -154,9 +508,43 @@ func (in *LustreFileSystemNamespaceStatus) DeepCopy() *LustreFileSystemNamespace
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LustreFileSystemReference) DeepCopyInto(out *LustreFileSystemReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemReference.
+func (in *LustreFileSystemReference) DeepCopy() *LustreFileSystemReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LustreFileSystemReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LustreFileSystemSpec) DeepCopyInto(out *LustreFileSystemSpec) {
 	*out = *in
+	if in.MgsNodes != nil {
+		in, out := &in.MgsNodes, &out.MgsNodes
+		*out = make([]LustreFileSystemMgsNode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MountOptions != nil {
+		in, out := &in.MountOptions, &out.MountOptions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.VolumeAttributes != nil {
+		in, out := &in.VolumeAttributes, &out.VolumeAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Namespaces != nil {
 		in, out := &in.Namespaces, &out.Namespaces
 		*out = make(map[string]LustreFileSystemNamespaceSpec, len(*in))
@@ -164,6 +552,21 @@ func (in *LustreFileSystemSpec) DeepCopyInto(out *LustreFileSystemSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(LustreFileSystemNamespaceSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevocationGracePeriodSeconds != nil {
+		in, out := &in.RevocationGracePeriodSeconds, &out.RevocationGracePeriodSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.AccessPolicy != nil {
+		in, out := &in.AccessPolicy, &out.AccessPolicy
+		*out = new(LustreFileSystemAccessPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemSpec.
@@ -186,6 +589,18 @@ func (in *LustreFileSystemStatus) DeepCopyInto(out *LustreFileSystemStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletionBlockedBy != nil {
+		in, out := &in.DeletionBlockedBy, &out.DeletionBlockedBy
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LustreFileSystemStatus.
@@ -197,3 +612,18 @@ func (in *LustreFileSystemStatus) DeepCopy() *LustreFileSystemStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SubdirectoryTemplateData) DeepCopyInto(out *SubdirectoryTemplateData) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SubdirectoryTemplateData.
+func (in *SubdirectoryTemplateData) DeepCopy() *SubdirectoryTemplateData {
+	if in == nil {
+		return nil
+	}
+	out := new(SubdirectoryTemplateData)
+	in.DeepCopyInto(out)
+	return out
+}
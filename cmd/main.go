@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2024 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -22,11 +22,15 @@ package main
 import (
 	"flag"
 	"os"
+	"strings"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -34,10 +38,13 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	lusv1alpha1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1alpha1"
 	lusv1beta1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1beta1"
+	"github.com/NearNodeFlash/lustre-fs-operator/internal/config"
 	controllers "github.com/NearNodeFlash/lustre-fs-operator/internal/controller"
+	"github.com/NearNodeFlash/lustre-fs-operator/internal/lfs"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -55,14 +62,47 @@ func init() {
 }
 
 func main() {
+	var configFile string
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
+	var webhookPort int
+	var defaultCSIDriver string
+	var defaultStorageClass string
+	var revocationGracePeriod time.Duration
+	var defaultModes string
+	var defaultMountOptions string
+	var maxConcurrentReconciles int
+	var resyncPeriod time.Duration
+	var lfsCommand string
+	var lfsTimeout time.Duration
+	flag.StringVar(&configFile, "config", "",
+		"The operator configuration file. Its settings override the command line flags, and its defaults and "+
+			"ignored namespaces are reloaded when it changes.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server listens on.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
+	flag.StringVar(&defaultCSIDriver, "default-csi-driver", "",
+		"The name of the CSI driver used by LustreFileSystems that don't specify one.")
+	flag.StringVar(&defaultStorageClass, "default-storage-class", lusv1beta1.DefaultStorageClassName,
+		"The name of the storage class used by LustreFileSystems that don't specify one.")
+	flag.DurationVar(&revocationGracePeriod, "revocation-grace-period", time.Hour,
+		"How long a revoked namespace access waits for the pods using it to finish, for LustreFileSystems that don't specify a grace period.")
+	flag.StringVar(&defaultModes, "default-modes", string(corev1.ReadWriteMany),
+		"The comma separated access modes granted to the namespaces of a LustreFileSystem that don't list any.")
+	flag.StringVar(&defaultMountOptions, "default-mount-options", "",
+		"The comma separated mount options used by LustreFileSystems that don't specify any.")
+	flag.IntVar(&maxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of LustreFileSystems reconciled at the same time.")
+	flag.DurationVar(&resyncPeriod, "resync-period", 0,
+		"How often every LustreFileSystem is reconciled when nothing changed. Zero uses the default of the manager.")
+	flag.StringVar(&lfsCommand, "lfs-command", "",
+		"The path of the lfs command used to manage the project quotas of the namespaces. Project quotas aren't managed when it's empty.")
+	flag.DurationVar(&lfsTimeout, "lfs-timeout", 10*time.Minute,
+		"How long an lfs command may run. Zero doesn't bound the commands.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -71,30 +111,129 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	// The flags are the base of the configuration file, which is read over them
+	flagConfig := func() config.OperatorConfig {
+		modes := []corev1.PersistentVolumeAccessMode{}
+		for _, mode := range splitList(defaultModes) {
+			modes = append(modes, corev1.PersistentVolumeAccessMode(mode))
+		}
+
+		return config.OperatorConfig{
+			TypeMeta:       metav1.TypeMeta{APIVersion: config.APIVersion, Kind: config.Kind},
+			Health:         config.HealthConfig{HealthProbeBindAddress: probeAddr},
+			Metrics:        config.MetricsConfig{BindAddress: metricsAddr},
+			Webhook:        config.WebhookConfig{Port: webhookPort},
+			LeaderElection: config.LeaderElectionConfig{LeaderElect: enableLeaderElection, ResourceName: "27a5a5a9.cray.hpe.com"},
+			Controller: config.ControllerConfig{
+				MaxConcurrentReconciles: maxConcurrentReconciles,
+				ResyncPeriod:            metav1.Duration{Duration: resyncPeriod},
+				LfsCommand:              lfsCommand,
+				LfsTimeout:              metav1.Duration{Duration: lfsTimeout},
+			},
+			Defaults: config.DefaultsConfig{
+				CSIDriver:             defaultCSIDriver,
+				StorageClassName:      defaultStorageClass,
+				Modes:                 modes,
+				MountOptions:          splitList(defaultMountOptions),
+				RevocationGracePeriod: metav1.Duration{Duration: revocationGracePeriod},
+			},
+		}
+	}
+
+	cfg := flagConfig()
+	if len(configFile) != 0 {
+		if err := config.Load(configFile, &cfg); err != nil {
+			setupLog.Error(err, "unable to load the configuration file", "path", configFile)
+			os.Exit(1)
+		}
+	} else if err := cfg.Validate(); err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+
+	if len(cfg.Defaults.CSIDriver) == 0 {
+		setupLog.Info("no default CSI driver; every LustreFileSystem must specify one")
+	}
+
+	ignoredNamespaces := &controllers.NamespaceFilter{}
+	applyConfig := func(cfg config.OperatorConfig) {
+		lusv1beta1.SetDefaults(lusv1beta1.LustreFileSystemDefaults{
+			CSIDriver:             cfg.Defaults.CSIDriver,
+			StorageClassName:      cfg.Defaults.StorageClassName,
+			Modes:                 cfg.Defaults.Modes,
+			MountOptions:          cfg.Defaults.MountOptions,
+			RevocationGracePeriod: cfg.Defaults.RevocationGracePeriod.Duration,
+		})
+
+		ignoredNamespaces.Set(cfg.IgnoredNamespaceSelectors())
+	}
+
+	applyConfig(cfg)
+
+	cacheOptions := controllers.ManagerCacheOptions()
+	if cfg.Controller.ResyncPeriod.Duration != 0 {
+		cacheOptions.SyncPeriod = &cfg.Controller.ResyncPeriod.Duration
+	}
+
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:                 scheme,
-		Metrics:                metricsserver.Options{BindAddress: metricsAddr},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "27a5a5a9.cray.hpe.com",
+		Metrics:                metricsserver.Options{BindAddress: cfg.Metrics.BindAddress},
+		WebhookServer:          webhook.NewServer(webhook.Options{Port: cfg.Webhook.Port}),
+		HealthProbeBindAddress: cfg.Health.HealthProbeBindAddress,
+		LeaderElection:         cfg.LeaderElection.LeaderElect,
+		LeaderElectionID:       cfg.LeaderElection.ResourceName,
+		Cache:                  cacheOptions,
+		Client:                 controllers.ManagerClientOptions(),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	if len(configFile) != 0 {
+		if err := mgr.Add(config.NewWatcher(configFile, cfg, flagConfig, applyConfig)); err != nil {
+			setupLog.Error(err, "unable to watch the configuration file")
+			os.Exit(1)
+		}
+	}
+
+	// The project quotas are only managed where the lfs command is available
+	var quotas lfs.Runner
+	if len(cfg.Controller.LfsCommand) != 0 {
+		quotas = &lfs.ExecRunner{Command: cfg.Controller.LfsCommand, Timeout: cfg.Controller.LfsTimeout.Duration}
+	}
+
 	if err = (&controllers.LustreFileSystemReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("lustre-fs-operator"),
+
+		IgnoredNamespaces:       ignoredNamespaces,
+		MaxConcurrentReconciles: cfg.Controller.MaxConcurrentReconciles,
+		Quotas:                  quotas,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LustreFileSystem")
 		os.Exit(1)
 	}
+	if err = (&controllers.LustreFileSystemAccessReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("lustre-fs-operator"),
+
+		IgnoredNamespaces: ignoredNamespaces,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "LustreFileSystemAccess")
+		os.Exit(1)
+	}
 	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
 		if err = (&lusv1beta1.LustreFileSystem{}).SetupWebhookWithManager(mgr); err != nil {
 			setupLog.Error(err, "unable to create webhook", "webhook", "LustreFileSystem")
 			os.Exit(1)
 		}
+		if err = (&lusv1beta1.LustreFileSystemAccess{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "LustreFileSystemAccess")
+			os.Exit(1)
+		}
 	}
 	//+kubebuilder:scaffold:builder
 
@@ -113,3 +252,15 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// splitList splits a comma separated flag value, dropping the empty entries
+func splitList(value string) []string {
+	list := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); len(entry) != 0 {
+			list = append(list, entry)
+		}
+	}
+
+	return list
+}
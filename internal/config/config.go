@@ -0,0 +1,259 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion is the version of the operator configuration file
+	APIVersion = "config.lus.cray.hpe.com/v1alpha1"
+
+	// Kind is the kind of the operator configuration file
+	Kind = "OperatorConfig"
+)
+
+// OperatorConfig is the configuration file of the operator. The health, metrics, webhook, leader election
+// and controller settings are read when the operator starts; the defaults and the ignored namespaces are
+// reloaded when the file changes.
+type OperatorConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Health configures the health probe endpoint
+	Health HealthConfig `json:"health,omitempty"`
+
+	// Metrics configures the metrics endpoint
+	Metrics MetricsConfig `json:"metrics,omitempty"`
+
+	// Webhook configures the webhook server
+	Webhook WebhookConfig `json:"webhook,omitempty"`
+
+	// LeaderElection configures the leader election of the controller managers
+	LeaderElection LeaderElectionConfig `json:"leaderElection,omitempty"`
+
+	// Controller configures the LustreFileSystem controller
+	Controller ControllerConfig `json:"controller,omitempty"`
+
+	// Defaults are applied to the LustreFileSystems that don't specify their own values
+	Defaults DefaultsConfig `json:"defaults,omitempty"`
+
+	// IgnoreNamespaceSelectors select the namespaces that are never granted access through the namespace
+	// selector of a LustreFileSystem. A namespace listed by name in a LustreFileSystem is not ignored.
+	IgnoreNamespaceSelectors []metav1.LabelSelector `json:"ignoreNamespaceSelectors,omitempty"`
+}
+
+// HealthConfig configures the health probe endpoint
+type HealthConfig struct {
+	// HealthProbeBindAddress is the address the health probe endpoint binds to
+	HealthProbeBindAddress string `json:"healthProbeBindAddress,omitempty"`
+}
+
+// MetricsConfig configures the metrics endpoint
+type MetricsConfig struct {
+	// BindAddress is the address the metrics endpoint binds to, or "0" to disable the endpoint
+	BindAddress string `json:"bindAddress,omitempty"`
+}
+
+// WebhookConfig configures the webhook server
+type WebhookConfig struct {
+	// Port is the port the webhook server listens on
+	Port int `json:"port,omitempty"`
+}
+
+// LeaderElectionConfig configures the leader election of the controller managers
+type LeaderElectionConfig struct {
+	// LeaderElect enables leader election, so only one controller manager is active
+	LeaderElect bool `json:"leaderElect,omitempty"`
+
+	// ResourceName is the name of the lock used for leader election
+	ResourceName string `json:"resourceName,omitempty"`
+}
+
+// ControllerConfig configures the LustreFileSystem controller
+type ControllerConfig struct {
+	// MaxConcurrentReconciles is the number of LustreFileSystems reconciled at the same time
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+
+	// ResyncPeriod is how often every LustreFileSystem is reconciled when nothing changed. Zero uses the
+	// default of the manager.
+	ResyncPeriod metav1.Duration `json:"resyncPeriod,omitempty"`
+
+	// LfsCommand is the path of the lfs command used to manage the project quotas of the namespaces. The
+	// project quotas aren't managed when it's empty. Each LustreFileSystem with a quota must be mounted at
+	// its mount root where the operator runs.
+	LfsCommand string `json:"lfsCommand,omitempty"`
+
+	// LfsTimeout is how long an lfs command may run. The commands run in the reconcile worker, and assigning a
+	// project ID walks the whole namespace subdirectory, so the timeout keeps a large subdirectory from holding up
+	// every file system. Zero doesn't bound the commands.
+	LfsTimeout metav1.Duration `json:"lfsTimeout,omitempty"`
+}
+
+// DefaultsConfig holds the defaults applied to the LustreFileSystems that don't specify their own values
+type DefaultsConfig struct {
+	// CSIDriver is the name of the CSI driver
+	CSIDriver string `json:"csiDriver,omitempty"`
+
+	// StorageClassName is the name of the storage class
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// Modes are the access modes granted to a namespace that doesn't list any
+	Modes []corev1.PersistentVolumeAccessMode `json:"modes,omitempty"`
+
+	// MountOptions are the mount options
+	MountOptions []string `json:"mountOptions,omitempty"`
+
+	// RevocationGracePeriod is how long a revoked namespace access waits for the pods using it to finish
+	RevocationGracePeriod metav1.Duration `json:"revocationGracePeriod,omitempty"`
+}
+
+// Load reads the configuration file at path over cfg, so settings missing from the file keep the values
+// already in cfg, and validates the result
+func Load(path string, cfg *OperatorConfig) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	return Parse(data, cfg)
+}
+
+// Parse decodes the configuration over cfg, so settings missing from the data keep the values already in
+// cfg, and validates the result
+func Parse(data []byte, cfg *OperatorConfig) error {
+	// Decode over a deep copy so the slices and maps of cfg aren't modified when the data is invalid
+	base, err := json.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	loaded := OperatorConfig{}
+	if err := json.Unmarshal(base, &loaded); err != nil {
+		return err
+	}
+
+	if err := yaml.UnmarshalStrict(data, &loaded); err != nil {
+		return err
+	}
+
+	if loaded.APIVersion != APIVersion || loaded.Kind != Kind {
+		return fmt.Errorf("unsupported configuration '%s, Kind=%s'; expected '%s, Kind=%s'", loaded.APIVersion, loaded.Kind, APIVersion, Kind)
+	}
+
+	if err := loaded.Validate(); err != nil {
+		return err
+	}
+
+	*cfg = loaded
+	return nil
+}
+
+// supportedModes are the access modes a LustreFileSystem can be granted with
+var supportedModes = map[corev1.PersistentVolumeAccessMode]bool{
+	corev1.ReadWriteMany: true,
+	corev1.ReadWriteOnce: true,
+	corev1.ReadOnlyMany:  true,
+}
+
+// Validate checks the settings of the configuration
+func (cfg *OperatorConfig) Validate() error {
+	var errList field.ErrorList
+
+	if port := cfg.Webhook.Port; port < 1 || port > 65535 {
+		errList = append(errList, field.Invalid(field.NewPath("webhook", "port"), port, "must be between 1 and 65535"))
+	}
+
+	if cfg.LeaderElection.LeaderElect && len(cfg.LeaderElection.ResourceName) == 0 {
+		errList = append(errList, field.Required(field.NewPath("leaderElection", "resourceName"), "required when leader election is enabled"))
+	}
+
+	if cfg.Controller.MaxConcurrentReconciles < 1 {
+		errList = append(errList, field.Invalid(field.NewPath("controller", "maxConcurrentReconciles"), cfg.Controller.MaxConcurrentReconciles, "must be at least 1"))
+	}
+
+	if cfg.Controller.ResyncPeriod.Duration < 0 {
+		errList = append(errList, field.Invalid(field.NewPath("controller", "resyncPeriod"), cfg.Controller.ResyncPeriod.String(), "must not be negative"))
+	}
+
+	if cfg.Controller.LfsTimeout.Duration < 0 {
+		errList = append(errList, field.Invalid(field.NewPath("controller", "lfsTimeout"), cfg.Controller.LfsTimeout.String(), "must not be negative"))
+	}
+
+	f := field.NewPath("defaults")
+	for i, mode := range cfg.Defaults.Modes {
+		if !supportedModes[mode] {
+			errList = append(errList, field.NotSupported(f.Child("modes").Index(i), mode, []string{string(corev1.ReadWriteMany), string(corev1.ReadWriteOnce), string(corev1.ReadOnlyMany)}))
+		}
+	}
+
+	if cfg.Defaults.RevocationGracePeriod.Duration < 0 {
+		errList = append(errList, field.Invalid(f.Child("revocationGracePeriod"), cfg.Defaults.RevocationGracePeriod.String(), "must not be negative"))
+	}
+
+	for i := range cfg.IgnoreNamespaceSelectors {
+		if _, err := metav1.LabelSelectorAsSelector(&cfg.IgnoreNamespaceSelectors[i]); err != nil {
+			errList = append(errList, field.Invalid(field.NewPath("ignoreNamespaceSelectors").Index(i), cfg.IgnoreNamespaceSelectors[i], err.Error()))
+		}
+	}
+
+	return errList.ToAggregate()
+}
+
+// IgnoredNamespaceSelectors returns the selectors of the ignored namespaces. The configuration must be valid.
+func (cfg *OperatorConfig) IgnoredNamespaceSelectors() []labels.Selector {
+	selectors := make([]labels.Selector, 0, len(cfg.IgnoreNamespaceSelectors))
+	for i := range cfg.IgnoreNamespaceSelectors {
+		selector, _ := metav1.LabelSelectorAsSelector(&cfg.IgnoreNamespaceSelectors[i])
+		selectors = append(selectors, selector)
+	}
+
+	return selectors
+}
+
+// StructuralChanges returns the settings that differ from the other configuration and only take effect when
+// the operator restarts
+func (cfg *OperatorConfig) StructuralChanges(other *OperatorConfig) []string {
+	changes := []string{}
+	for name, equal := range map[string]bool{
+		"health":         cfg.Health == other.Health,
+		"metrics":        cfg.Metrics == other.Metrics,
+		"webhook":        cfg.Webhook == other.Webhook,
+		"leaderElection": cfg.LeaderElection == other.LeaderElection,
+		"controller":     equality.Semantic.DeepEqual(cfg.Controller, other.Controller),
+	} {
+		if !equal {
+			changes = append(changes, name)
+		}
+	}
+
+	slices.Sort(changes)
+	return changes
+}
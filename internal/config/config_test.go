@@ -0,0 +1,142 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+var _ = Describe("OperatorConfig", func() {
+
+	var base OperatorConfig
+
+	BeforeEach(func() {
+		base = OperatorConfig{
+			TypeMeta:       metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind},
+			Health:         HealthConfig{HealthProbeBindAddress: ":8081"},
+			Metrics:        MetricsConfig{BindAddress: ":8080"},
+			Webhook:        WebhookConfig{Port: 9443},
+			LeaderElection: LeaderElectionConfig{ResourceName: "test"},
+			Controller:     ControllerConfig{MaxConcurrentReconciles: 1},
+			Defaults: DefaultsConfig{
+				CSIDriver: "flag-driver",
+				Modes:     []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			},
+		}
+	})
+
+	It("loads the operator's configuration file", func() {
+		cfg := base
+		Expect(Load(filepath.Join("..", "..", "config", "manager", "controller_manager_config.yaml"), &cfg)).To(Succeed())
+		Expect(cfg.LeaderElection.LeaderElect).To(BeTrue())
+		Expect(cfg.Defaults.CSIDriver).To(Equal("lustre-csi.hpe.com"))
+
+		namespace := labels.Set{"kubernetes.io/metadata.name": "kube-system"}
+		Expect(cfg.IgnoredNamespaceSelectors()).To(ContainElement(WithTransform(func(s labels.Selector) bool {
+			return s.Matches(namespace)
+		}, BeTrue())))
+	})
+
+	It("keeps the settings missing from the file", func() {
+		cfg := base
+		Expect(Parse([]byte(`
+apiVersion: config.lus.cray.hpe.com/v1alpha1
+kind: OperatorConfig
+defaults:
+  mountOptions: [flock]
+`), &cfg)).To(Succeed())
+		Expect(cfg.Defaults.CSIDriver).To(Equal("flag-driver"))
+		Expect(cfg.Defaults.MountOptions).To(Equal([]string{"flock"}))
+		Expect(cfg.Webhook.Port).To(Equal(9443))
+	})
+
+	DescribeTable("rejects invalid configurations",
+		func(data string) {
+			cfg := base
+			Expect(Parse([]byte(data), &cfg)).NotTo(Succeed())
+			Expect(cfg).To(Equal(base))
+		},
+		Entry("with another kind", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: ControllerManagerConfig\n"),
+		Entry("with an unknown field", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\nunknown: true\n"),
+		Entry("with an invalid port", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\nwebhook:\n  port: 70000\n"),
+		Entry("with no concurrent reconciles", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\ncontroller:\n  maxConcurrentReconciles: 0\n"),
+		Entry("with a negative lfs timeout", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\ncontroller:\n  lfsTimeout: -1m\n"),
+		Entry("with an unsupported mode", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\ndefaults:\n  modes: [ReadWriteOncePod]\n"),
+		Entry("with an invalid selector", "apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\nignoreNamespaceSelectors:\n- matchExpressions:\n  - {key: a, operator: Bad}\n"),
+	)
+
+	It("reports the settings that need a restart", func() {
+		cfg := base
+		cfg.Webhook.Port = 9444
+		cfg.Defaults.CSIDriver = "other-driver"
+		Expect(cfg.StructuralChanges(&base)).To(Equal([]string{"webhook"}))
+	})
+})
+
+var _ = Describe("Watcher", func() {
+
+	It("applies a changed configuration file and ignores an invalid one", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		write := func(data string) {
+			Expect(os.WriteFile(path, []byte("apiVersion: config.lus.cray.hpe.com/v1alpha1\nkind: OperatorConfig\n"+data), 0600)).To(Succeed())
+		}
+
+		base := func() OperatorConfig {
+			return OperatorConfig{
+				TypeMeta:   metav1.TypeMeta{APIVersion: APIVersion, Kind: Kind},
+				Webhook:    WebhookConfig{Port: 9443},
+				Controller: ControllerConfig{MaxConcurrentReconciles: 1},
+			}
+		}
+
+		write("defaults:\n  csiDriver: first\n")
+		cfg := base()
+		Expect(Load(path, &cfg)).To(Succeed())
+
+		applied := []string{}
+		w := NewWatcher(path, cfg, base, func(cfg OperatorConfig) {
+			applied = append(applied, cfg.Defaults.CSIDriver)
+		})
+
+		By("leaving an unchanged file alone")
+		w.Reload(context.TODO())
+		Expect(applied).To(BeEmpty())
+
+		By("applying a changed file")
+		write("defaults:\n  csiDriver: second\n  revocationGracePeriod: 5m\n")
+		w.Reload(context.TODO())
+		Expect(applied).To(Equal([]string{"second"}))
+		Expect(w.current.Defaults.RevocationGracePeriod.Duration).To(Equal(5 * time.Minute))
+
+		By("ignoring an invalid file")
+		write("defaults:\n  csiDriver: third\n  modes: [Bad]\n")
+		w.Reload(context.TODO())
+		Expect(applied).To(Equal([]string{"second"}))
+	})
+})
@@ -0,0 +1,125 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package config
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// defaultPollInterval is how often the watcher reads the configuration file
+const defaultPollInterval = 10 * time.Second
+
+// Watcher reloads the configuration file when it changes. The file is polled rather than watched for events
+// since a ConfigMap volume replaces the file through a symbolic link.
+type Watcher struct {
+	// Path is the path of the configuration file
+	Path string
+
+	// Base returns the configuration the file is read over, built from the command line flags
+	Base func() OperatorConfig
+
+	// Apply is called with the reloaded configuration when the file changes and the configuration is valid
+	Apply func(OperatorConfig)
+
+	// PollInterval is how often the file is read. Zero uses a default of ten seconds.
+	PollInterval time.Duration
+
+	current OperatorConfig
+	data    []byte
+}
+
+var _ manager.Runnable = &Watcher{}
+var _ manager.LeaderElectionRunnable = &Watcher{}
+
+// NewWatcher returns a watcher for the configuration file at path, which was loaded as current
+func NewWatcher(path string, current OperatorConfig, base func() OperatorConfig, apply func(OperatorConfig)) *Watcher {
+	data, _ := os.ReadFile(path)
+
+	return &Watcher{
+		Path:    path,
+		Base:    base,
+		Apply:   apply,
+		current: current,
+		data:    data,
+	}
+}
+
+// NeedLeaderElection returns false since the defaults are used by the webhooks of every controller manager
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start polls the configuration file until the context is done
+func (w *Watcher) Start(ctx context.Context) error {
+	interval := w.PollInterval
+	if interval == 0 {
+		interval = defaultPollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			w.Reload(ctx)
+		}
+	}
+}
+
+// Reload reads the configuration file and applies it when it changed. An invalid configuration is logged and
+// the previous configuration stays in effect.
+func (w *Watcher) Reload(ctx context.Context) {
+	log := log.FromContext(ctx).WithName("config").WithValues("path", w.Path)
+
+	data, err := os.ReadFile(w.Path)
+	if err != nil {
+		log.Error(err, "failed to read the configuration file")
+		return
+	}
+
+	if bytes.Equal(data, w.data) {
+		return
+	}
+
+	w.data = data
+
+	cfg := w.Base()
+	if err := Parse(data, &cfg); err != nil {
+		log.Error(err, "invalid configuration file; keeping the previous configuration")
+		return
+	}
+
+	if changes := cfg.StructuralChanges(&w.current); len(changes) != 0 {
+		log.Info("configuration changes take effect when the operator restarts", "settings", changes)
+	}
+
+	log.Info("reloaded the configuration file")
+	w.Apply(cfg)
+	w.current = cfg
+}
@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2024 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -21,29 +21,101 @@ package controller
 
 import (
 	"context"
-	"os"
-
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/DataWorkflowServices/dws/utils/updater"
 	lusv1beta1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1beta1"
+	"github.com/NearNodeFlash/lustre-fs-operator/internal/controller/metrics"
+	"github.com/NearNodeFlash/lustre-fs-operator/internal/lfs"
 )
 
 const (
 	finalizerLustreFileSystem = "lus.cray.hpe.com/lustre_fs"
+
+	// namespacesIndexField indexes LustreFileSystem objects by the namespaces they grant access to
+	namespacesIndexField = "spec.namespaces"
+
+	// namespaceSelectorIndexField indexes LustreFileSystem objects that have a namespace selector
+	namespaceSelectorIndexField = "spec.namespaceSelector"
+
+	// storageClassIndexField indexes LustreFileSystem objects by the name of their storage class
+	storageClassIndexField = "spec.storageClassName"
+
+	// fileSystemRefIndexField indexes LustreFileSystemAccess objects by the "namespace/name" of the file system
+	// they request access to
+	fileSystemRefIndexField = "spec.fileSystemRef"
+
+	// noProvisioner is the provisioner of a storage class whose persistent volumes are only created statically
+	noProvisioner = "kubernetes.io/no-provisioner"
+
+	// podsInUseRequeueInterval is how often a claim waiting to move to a new persistent volume, or a file
+	// system waiting to be deleted, is checked for pods still using it. Pods aren't watched, so their
+	// completion doesn't trigger a reconcile.
+	podsInUseRequeueInterval = 30 * time.Second
+
+	// quotaUsageRefreshInterval is how often the usage of the namespace project quotas is read
+	quotaUsageRefreshInterval = 5 * time.Minute
+
+	// firstProjectID is the lowest Lustre project ID allocated to a namespace. The IDs below it are left for the
+	// projects an administrator manages outside of the operator.
+	firstProjectID = int64(1000)
+)
+
+// Event reasons recorded against the LustreFileSystem and the persistent volume claims it manages
+const (
+	eventReasonAccessGranted                = "AccessGranted"
+	eventReasonAccessRevoked                = "AccessRevoked"
+	eventReasonAccessRevoking               = "AccessRevoking"
+	eventReasonAccessExpired                = "AccessExpired"
+	eventReasonAccessError                  = "AccessError"
+	eventReasonNamespaceNotFound            = "NamespaceNotFound"
+	eventReasonNamespaceTerminating         = "NamespaceTerminating"
+	eventReasonPVConflict                   = "PVConflict"
+	eventReasonPVCConflict                  = "PVCConflict"
+	eventReasonPVCBindFailed                = "PVCBindFailed"
+	eventReasonPersistentVolumeDeleted      = "PersistentVolumeDeleted"
+	eventReasonPersistentVolumeClaimDeleted = "PersistentVolumeClaimDeleted"
+	eventReasonFinalizerRemoved             = "FinalizerRemoved"
+	eventReasonRolloverPending              = "RolloverPending"
+	eventReasonRolloverComplete             = "RolloverComplete"
+	eventReasonDeletionBlocked              = "DeletionBlocked"
+	eventReasonStorageClassCreated          = "StorageClassCreated"
+	eventReasonStorageClassDeleted          = "StorageClassDeleted"
+	eventReasonStorageClassUnavailable      = "StorageClassUnavailable"
+	eventReasonQuotaApplied                 = "QuotaApplied"
+	eventReasonQuotaRemoved                 = "QuotaRemoved"
+	eventReasonQuotaError                   = "QuotaError"
 )
 
 var (
@@ -52,18 +124,154 @@ var (
 	persistentVolumeResourceQuantity = resource.MustParse("1")
 )
 
+// persistentVolumeConflictError is returned when the persistent volume for an access belongs to another file
+// system or is bound to another claim
+type persistentVolumeConflictError struct {
+	name  string
+	owner string
+	claim string
+}
+
+func (e *persistentVolumeConflictError) Error() string {
+	if len(e.owner) != 0 {
+		return fmt.Sprintf("persistent volume '%s' belongs to LustreFileSystem '%s'", e.name, e.owner)
+	}
+
+	return fmt.Sprintf("persistent volume '%s' is bound to claim '%s'", e.name, e.claim)
+}
+
+// persistentVolumeClaimConflictError is returned when the claim for an access exists and isn't managed by the file system
+type persistentVolumeClaimConflictError struct {
+	name string
+}
+
+func (e *persistentVolumeClaimConflictError) Error() string {
+	return fmt.Sprintf("persistent volume claim '%s' exists and is not managed by this file system", e.name)
+}
+
+// persistentVolumeSourceChangedError is returned when the volume source of an existing persistent volume
+// no longer matches the LustreFileSystem. The volume source is immutable, so the persistent volume is
+// deleted and recreated if it is not bound.
+type persistentVolumeSourceChangedError struct {
+	name string
+}
+
+func (e *persistentVolumeSourceChangedError) Error() string {
+	return fmt.Sprintf("persistent volume '%s' has a volume source that does not match the file system", e.name)
+}
+
+// accessError is returned when a namespace access could not be granted
+type accessError struct {
+	state lusv1beta1.NamespaceAccessState
+	err   error
+}
+
+func (e *accessError) Error() string {
+	return e.err.Error()
+}
+
+func (e *accessError) Unwrap() error {
+	return e.err
+}
+
+// quotaError is returned when the project quota of a namespace could not be set, read or removed
+type quotaError struct {
+	namespace string
+	err       error
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("namespace '%s' quota: %v", e.namespace, e.err)
+}
+
+func (e *quotaError) Unwrap() error {
+	return e.err
+}
+
+// reconcileErrorReason returns the reason recorded in the reconcile error metric for the error
+func reconcileErrorReason(err error) string {
+	if aggregate, ok := err.(utilerrors.Aggregate); ok && len(aggregate.Errors()) != 0 {
+		err = aggregate.Errors()[0]
+	}
+
+	if accessErr, ok := err.(*accessError); ok {
+		return string(accessErr.state)
+	}
+
+	if _, ok := err.(*quotaError); ok {
+		return eventReasonQuotaError
+	}
+
+	if reason := errors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		return string(reason)
+	}
+
+	return "Unknown"
+}
+
 // LustreFileSystemReconciler reconciles a LustreFileSystem object
 type LustreFileSystemReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// IgnoredNamespaces filters the namespaces matching a namespace selector. It may be nil.
+	IgnoredNamespaces *NamespaceFilter
+
+	// MaxConcurrentReconciles is the number of LustreFileSystems reconciled at the same time. Zero uses
+	// the default of the controller.
+	MaxConcurrentReconciles int
+
+	// Quotas runs the lfs commands that manage the project quotas of the namespaces. The project quotas
+	// aren't managed when it's nil.
+	Quotas lfs.Runner
+}
+
+// NamespaceFilter holds the selectors of the namespaces that are never granted access through the namespace
+// selector of a LustreFileSystem. The selectors may be changed while the controller runs; the change takes
+// effect the next time each LustreFileSystem is reconciled.
+type NamespaceFilter struct {
+	lock      sync.RWMutex
+	selectors []labels.Selector
+}
+
+// Set replaces the selectors of the ignored namespaces
+func (f *NamespaceFilter) Set(selectors []labels.Selector) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.selectors = selectors
+}
+
+// Ignored returns true when the namespace matches one of the selectors
+func (f *NamespaceFilter) Ignored(ns *corev1.Namespace) bool {
+	if f == nil {
+		return false
+	}
+
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+
+	for _, selector := range f.selectors {
+		if selector.Matches(labels.Set(ns.GetLabels())) {
+			return true
+		}
+	}
+
+	return false
 }
 
 //+kubebuilder:rbac:groups=lus.cray.hpe.com,resources=lustrefilesystems,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lus.cray.hpe.com,resources=lustrefilesystems/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=lus.cray.hpe.com,resources=lustrefilesystemaccesses,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lus.cray.hpe.com,resources=lustrefilesystems/finalizers,verbs=update
 //+kubebuilder:rbac:groups=core,resources=persistentvolumes,verbs=get;list;update;create;patch;delete;watch
 //+kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;update;create;patch;delete;watch
 //+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -80,8 +288,17 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	// The namespaces granted access, from both the specification and the namespace selector
+	var accesses map[string]lusv1beta1.LustreFileSystemNamespaceSpec
+
 	statusUpdater := updater.NewStatusUpdater[*lusv1beta1.LustreFileSystemStatus](fs)
 	defer func() { err = statusUpdater.CloseWithStatusUpdate(ctx, r.Client.Status(), err) }()
+	defer func() { r.setConditions(fs, accesses, err) }()
+	defer func() {
+		if err != nil {
+			metrics.ReconcileErrorsTotal.WithLabelValues(reconcileErrorReason(err)).Inc()
+		}
+	}()
 
 	// Check if the object is being deleted.
 	if !fs.GetDeletionTimestamp().IsZero() {
@@ -105,19 +322,79 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			return ctrl.Result{}, nil
 		}
 
-		for namespace := range fs.Spec.Namespaces {
-			for _, mode := range fs.Spec.Namespaces[namespace].Modes {
+		accesses, _, err = r.getNamespaceAccesses(ctx, fs)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// An access being revoked, or no longer matched by the namespace selector, is only in the status, so the
+		// accesses in the status are removed along with those in the specification
+		deleting := map[string][]corev1.PersistentVolumeAccessMode{}
+		for namespace, spec := range accesses {
+			deleting[namespace] = slices.Clone(spec.Modes)
+		}
+
+		for namespace, namespaceStatus := range fs.Status.Namespaces {
+			for mode := range namespaceStatus.Modes {
+				if !slices.Contains(deleting[namespace], mode) {
+					deleting[namespace] = append(deleting[namespace], mode)
+				}
+			}
+		}
+
+		// Wait for the pods using the claims to finish unless the deletion is forced
+		var pods []string
+		if fs.GetAnnotations()[lusv1beta1.ForceDeleteAnnotation] != "true" {
+			for namespace, modes := range deleting {
+				claimNames := []string{}
+				for _, mode := range modes {
+					claimNames = append(claimNames, accessClaimName(fs, namespace, mode))
+				}
+
+				namespacePods, err := r.getPodsUsingClaims(ctx, namespace, claimNames...)
+				if err != nil {
+					return ctrl.Result{}, err
+				}
+
+				pods = append(pods, namespacePods...)
+			}
+		}
+
+		slices.Sort(pods)
+		if !slices.Equal(pods, fs.Status.DeletionBlockedBy) && len(pods) != 0 {
+			r.Recorder.Eventf(fs, corev1.EventTypeWarning, eventReasonDeletionBlocked, "Deletion is waiting for %d pod(s) using the file system to finish; set the '%s' annotation to 'true' to delete anyway", len(pods), lusv1beta1.ForceDeleteAnnotation)
+		}
+
+		fs.Status.DeletionBlockedBy = pods
+		if len(pods) != 0 {
+			return ctrl.Result{RequeueAfter: podsInUseRequeueInterval}, nil
+		}
+
+		for namespace, modes := range deleting {
+			for _, mode := range modes {
 				if err := r.deleteAccess(ctx, fs, namespace, mode); err != nil {
 					return ctrl.Result{}, err
 				}
 			}
 		}
 
+		for namespace := range fs.Status.Namespaces {
+			if err := r.removeQuota(ctx, fs, namespace); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if err := r.deleteStorageClass(ctx, fs); err != nil {
+			return ctrl.Result{}, err
+		}
+
 		controllerutil.RemoveFinalizer(fs, finalizerLustreFileSystem)
 		if err := r.Update(ctx, fs); err != nil {
 			return ctrl.Result{}, err
 		}
 
+		r.Recorder.Event(fs, corev1.EventTypeNormal, eventReasonFinalizerRemoved, "All namespace accesses removed; finalizer removed")
+
 		return ctrl.Result{}, nil
 	}
 
@@ -131,9 +408,24 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, nil
 	}
 
+	if err := r.reconcileStorageClass(ctx, fs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	accesses, selected, err := r.getNamespaceAccesses(ctx, fs)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Namespace accesses past their expiry are revoked like those removed from the specification, but they keep
+	// their status to record the expiration
+	expired, expiresIn := r.expireAccesses(fs, accesses)
+
 	// Iterate over the access modes in the specification. For each namespace in that mode
 	// create a PV/PVC which can be used by pods in the same namespace.
-	for namespace := range fs.Spec.Namespaces {
+	var errs []error
+	rolloverPending, revocationPending, quotaPresent := false, false, false
+	for namespace := range accesses {
 		namespacePresent := true
 
 		// If the namespace doesn't exist, set a flag so that we can appropriately set the status in the mode loop
@@ -151,47 +443,234 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			fs.Status.Namespaces = make(map[string]lusv1beta1.LustreFileSystemNamespaceStatus)
 		}
 
+		// The claims of the namespace that are waiting to move to a persistent volume with the current export path
+		rollover := &lusv1beta1.LustreFileSystemNamespaceRolloverStatus{}
+
 		// For each mode listed for the namespace
-		for _, mode := range fs.Spec.Namespaces[namespace].Modes {
+		for _, mode := range accesses[namespace].Modes {
 			// Create the Status Namespace Mode map if empty
-			if fs.Status.Namespaces[namespace].Modes == nil {
-				fs.Status.Namespaces[namespace] = lusv1beta1.LustreFileSystemNamespaceStatus{
-					Modes: make(map[corev1.PersistentVolumeAccessMode]lusv1beta1.LustreFileSystemNamespaceAccessStatus),
-				}
+			if namespaceStatus := fs.Status.Namespaces[namespace]; namespaceStatus.Modes == nil {
+				namespaceStatus.Modes = make(map[corev1.PersistentVolumeAccessMode]lusv1beta1.LustreFileSystemNamespaceAccessStatus)
+				fs.Status.Namespaces[namespace] = namespaceStatus
 			}
 
-			// Default the status as Pending in case the create/updates fail
-			fs.Status.Namespaces[namespace].Modes[mode] = lusv1beta1.LustreFileSystemNamespaceAccessStatus{
-				State: lusv1beta1.NamespaceAccessPending,
+			if namespaceStatus := fs.Status.Namespaces[namespace]; namespaceStatus.MatchedBySelector != selected[namespace] {
+				namespaceStatus.MatchedBySelector = selected[namespace]
+				fs.Status.Namespaces[namespace] = namespaceStatus
 			}
 
-			// If the namespace is not present or is not active, continue on and the status will be marked as Pending
-			if !namespacePresent || ns.Status.Phase != corev1.NamespaceActive {
+			subdirectory, err := fs.NamespaceSubdirectory(namespace, accesses[namespace])
+			if err != nil {
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:   lusv1beta1.NamespaceAccessError,
+					Message: fmt.Sprintf("invalid subdirectory: %v", err),
+				})
+				errs = append(errs, &accessError{state: lusv1beta1.NamespaceAccessError, err: err})
 				continue
 			}
 
-			// Attempt to create the PV, if it fails, the status will be marked as Pending
-			pv, err := r.createOrUpdatePersistentVolume(ctx, fs, namespace, mode)
+			exportPath := fs.ExportPath(subdirectory)
+			if namespaceStatus := fs.Status.Namespaces[namespace]; namespaceStatus.ExportPath != exportPath {
+				namespaceStatus.ExportPath = exportPath
+				fs.Status.Namespaces[namespace] = namespaceStatus
+			}
+
+			claimName, err := fs.NamespaceClaimName(namespace, accesses[namespace], mode)
 			if err != nil {
-				return ctrl.Result{}, err
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:   lusv1beta1.NamespaceAccessError,
+					Message: fmt.Sprintf("invalid claim name: %v", err),
+				})
+				errs = append(errs, &accessError{state: lusv1beta1.NamespaceAccessError, err: err})
+				continue
+			}
+
+			// If the namespace is not present or is not active, continue on and the status will explain why
+			if !namespacePresent {
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:   lusv1beta1.NamespaceAccessNamespaceNotFound,
+					Message: fmt.Sprintf("namespace '%s' does not exist", namespace),
+				})
+				continue
 			}
 
-			// Attempt to create the PVC, if it fails, the status will be marked as Pending
-			pvc, err := r.createOrUpdatePersistentVolumeClaim(ctx, fs, namespace, mode)
+			if ns.Status.Phase != corev1.NamespaceActive {
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:   lusv1beta1.NamespaceAccessNamespaceTerminating,
+					Message: fmt.Sprintf("namespace '%s' is in phase '%s'", namespace, ns.Status.Phase),
+				})
+				continue
+			}
+
+			pvName, previousPVName, err := r.getPersistentVolumeName(ctx, fs, namespace, mode, claimName, exportPath)
 			if err != nil {
-				return ctrl.Result{}, err
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:   lusv1beta1.NamespaceAccessError,
+					Message: err.Error(),
+				})
+				errs = append(errs, &accessError{state: lusv1beta1.NamespaceAccessError, err: err})
+				continue
+			}
+
+			// Attempt to create the PV, if it fails, the status will record the failure
+			pv, err := r.createOrUpdatePersistentVolume(ctx, fs, namespace, accesses[namespace], mode, claimName, pvName, exportPath)
+			if err != nil {
+				state := lusv1beta1.NamespaceAccessError
+				_, conflict := err.(*persistentVolumeConflictError)
+				_, changed := err.(*persistentVolumeSourceChangedError)
+				if conflict || changed || errors.IsInvalid(err) {
+					state = lusv1beta1.NamespaceAccessPVConflict
+				}
+
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:   state,
+					Message: err.Error(),
+				})
+				errs = append(errs, &accessError{state: state, err: err})
+				continue
+			}
+
+			pvRef := &corev1.LocalObjectReference{Name: pv.Name}
+
+			// The claim is bound to a persistent volume with a previous export path. It can't be rebound in place, so
+			// it is deleted and recreated bound to the new persistent volume once no pod is using it.
+			if len(previousPVName) != 0 {
+				rollover.PendingModes = append(rollover.PendingModes, mode)
+
+				pods, err := r.getPodsUsingClaims(ctx, namespace, claimName)
+				if err != nil {
+					r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+						State:   lusv1beta1.NamespaceAccessError,
+						Message: err.Error(),
+					})
+					errs = append(errs, &accessError{state: lusv1beta1.NamespaceAccessError, err: err})
+					continue
+				}
+
+				message := fmt.Sprintf("persistent volume claim '%s' is moving from persistent volume '%s' to '%s'", claimName, previousPVName, pv.Name)
+				if len(pods) != 0 {
+					rollover.Pods = append(rollover.Pods, pods...)
+					message = fmt.Sprintf("persistent volume claim '%s' is in use by %d pod(s) and will move from persistent volume '%s' to '%s' once they finish", claimName, len(pods), previousPVName, pv.Name)
+				} else if err := r.deletePersistentVolumeClaimForRollover(ctx, fs, namespace, claimName); err != nil {
+					r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+						State:   lusv1beta1.NamespaceAccessError,
+						Message: err.Error(),
+					})
+					errs = append(errs, &accessError{state: lusv1beta1.NamespaceAccessError, err: err})
+					continue
+				}
+
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:                    lusv1beta1.NamespaceAccessRolloverPending,
+					Message:                  message,
+					PersistentVolumeRef:      &corev1.LocalObjectReference{Name: previousPVName},
+					PersistentVolumeClaimRef: &corev1.LocalObjectReference{Name: claimName},
+				})
+				continue
+			}
+
+			// Attempt to create the PVC, if it fails, the status will record the failure
+			pvc, err := r.createOrUpdatePersistentVolumeClaim(ctx, fs, namespace, accesses[namespace], mode, claimName, pvName)
+			if err != nil {
+				state := lusv1beta1.NamespaceAccessError
+				if _, conflict := err.(*persistentVolumeClaimConflictError); conflict {
+					state = lusv1beta1.NamespaceAccessPVCConflict
+				} else if errors.IsInvalid(err) {
+					state = lusv1beta1.NamespaceAccessPVCBindFailed
+				}
+
+				r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:               state,
+					Message:             err.Error(),
+					PersistentVolumeRef: pvRef,
+				})
+				errs = append(errs, &accessError{state: state, err: err})
+				continue
+			}
+
+			pvcRef := &corev1.LocalObjectReference{Name: pvc.Name}
+
+			// A lost claim has had its PV deleted or rebound out from under it
+			if pvc.Status.Phase == corev1.ClaimLost {
+				message := fmt.Sprintf("persistent volume claim '%s' lost its persistent volume '%s'", pvc.Name, pv.Name)
+				if r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+					State:                    lusv1beta1.NamespaceAccessPVCBindFailed,
+					Message:                  message,
+					PersistentVolumeRef:      pvRef,
+					PersistentVolumeClaimRef: pvcRef,
+				}) {
+					r.Recorder.Event(pvc, corev1.EventTypeWarning, eventReasonPVCBindFailed, message)
+				}
+				continue
+			}
+
+			// Remove the persistent volumes with a previous export path or name once the claim is bound to its current persistent volume
+			if pvc.Status.Phase == corev1.ClaimBound && pvc.Spec.VolumeName == pv.Name {
+				if err := r.deletePreviousPersistentVolumes(ctx, fs, namespace, claimName, pv.Name); err != nil {
+					r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+						State:                    lusv1beta1.NamespaceAccessError,
+						Message:                  err.Error(),
+						PersistentVolumeRef:      pvRef,
+						PersistentVolumeClaimRef: pvcRef,
+					})
+					errs = append(errs, &accessError{state: lusv1beta1.NamespaceAccessError, err: err})
+					continue
+				}
 			}
 
 			// If we got this far, the status is Ready
-			fs.Status.Namespaces[namespace].Modes[mode] = lusv1beta1.LustreFileSystemNamespaceAccessStatus{
-				State: lusv1beta1.NamespaceAccessReady,
-				PersistentVolumeRef: &corev1.LocalObjectReference{
-					Name: pv.Name,
-				},
-				PersistentVolumeClaimRef: &corev1.LocalObjectReference{
-					Name: pvc.Name,
-				},
+			if r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+				State:                    lusv1beta1.NamespaceAccessReady,
+				PersistentVolumeRef:      pvRef,
+				PersistentVolumeClaimRef: pvcRef,
+			}) {
+				r.Recorder.Eventf(pvc, corev1.EventTypeNormal, eventReasonAccessGranted, "Access to Lustre file system '%s' granted by %s", fs.Spec.Name, client.ObjectKeyFromObject(fs))
+			}
+		}
+
+		r.setRolloverStatus(fs, namespace, rollover)
+		if len(rollover.PendingModes) != 0 {
+			rolloverPending = true
+		}
+
+		// The quota is left as it is while the namespace is missing or terminating
+		if namespacePresent && ns.Status.Phase == corev1.NamespaceActive {
+			if err := r.reconcileQuota(ctx, fs, namespace, accesses[namespace]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		// Without a runner the quota status only records that the quota isn't managed, so there's no usage to refresh
+		if r.Quotas != nil && fs.Status.Namespaces[namespace].Quota != nil {
+			quotaPresent = true
+		}
+	}
+
+	if len(errs) != 0 {
+		return ctrl.Result{}, utilerrors.NewAggregate(errs)
+	}
+
+	// Revoke the expired namespace accesses
+	for namespace, spec := range expired {
+		for _, mode := range spec.Modes {
+			if fs.Status.Namespaces[namespace].Modes[mode].State == lusv1beta1.NamespaceAccessExpired {
+				continue
+			}
+
+			revoked, err := r.revokeAccess(ctx, fs, namespace, mode)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+
+			if !revoked {
+				revocationPending = true
+				continue
 			}
+
+			r.setAccessStatus(fs, namespace, mode, lusv1beta1.LustreFileSystemNamespaceAccessStatus{
+				State:   lusv1beta1.NamespaceAccessExpired,
+				Message: fmt.Sprintf("access expired at %s", fs.Status.Namespaces[namespace].ExpiresAt.UTC().Format(time.RFC3339)),
+			})
 		}
 	}
 
@@ -200,7 +679,7 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		for mode := range fs.Status.Namespaces[namespace].Modes {
 			// Check if the provided namespace and mode are present in the specification
 			isPresentInSpec := func(namespace string, mode corev1.PersistentVolumeAccessMode) bool {
-				if ns, found := fs.Spec.Namespaces[namespace]; found {
+				if ns, found := accesses[namespace]; found {
 					for _, m := range ns.Modes {
 						if m == mode {
 							return true
@@ -212,18 +691,41 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 			}
 
 			if !isPresentInSpec(namespace, mode) {
-				if err := r.deleteAccess(ctx, fs, namespace, mode); err != nil {
+				// The expired accesses still in the specification were revoked above
+				if slices.Contains(expired[namespace].Modes, mode) {
+					continue
+				}
+
+				// An expired access that was removed from the specification has nothing left to revoke
+				if fs.Status.Namespaces[namespace].Modes[mode].State == lusv1beta1.NamespaceAccessExpired {
+					delete(fs.Status.Namespaces[namespace].Modes, mode)
+					return ctrl.Result{Requeue: true}, nil
+				}
+
+				revoked, err := r.revokeAccess(ctx, fs, namespace, mode)
+				if err != nil {
 					return ctrl.Result{}, err
 				}
 
+				if !revoked {
+					revocationPending = true
+					continue
+				}
+
 				delete(fs.Status.Namespaces[namespace].Modes, mode)
+				r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonAccessRevoked, "Namespace '%s' access %s revoked", namespace, mode)
 
 				// Force a requeue because we just modified the modes in place
 				return ctrl.Result{Requeue: true}, nil
 			}
 		}
 
-		if _, found := fs.Spec.Namespaces[namespace]; !found {
+		_, found := accesses[namespace]
+		if _, isExpired := expired[namespace]; !found && !isExpired && len(fs.Status.Namespaces[namespace].Modes) == 0 {
+			if err := r.removeQuota(ctx, fs, namespace); err != nil {
+				return ctrl.Result{}, err
+			}
+
 			delete(fs.Status.Namespaces, namespace)
 
 			// Force a requeue because we just modified the namespaces in place
@@ -231,131 +733,913 @@ func (r *LustreFileSystemReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		}
 	}
 
-	return ctrl.Result{}, nil
-}
+	if rolloverPending || revocationPending {
+		result.RequeueAfter = podsInUseRequeueInterval
+	}
 
-func (r *LustreFileSystemReconciler) createOrUpdatePersistentVolumeClaim(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) (*corev1.PersistentVolumeClaim, error) {
+	// Requeue precisely when the next namespace access expires
+	if expiresIn > 0 && (result.RequeueAfter == 0 || expiresIn < result.RequeueAfter) {
+		result.RequeueAfter = expiresIn
+	}
 
-	pvc := &corev1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fs.PersistentVolumeClaimName(namespace, mode),
-			Namespace: namespace,
-		},
+	// The usage of the quotas changes without any event to trigger a reconcile
+	if quotaPresent && (result.RequeueAfter == 0 || quotaUsageRefreshInterval < result.RequeueAfter) {
+		result.RequeueAfter = quotaUsageRefreshInterval
 	}
 
-	mutateFn := func() error {
-		pvc.Spec.StorageClassName = &fs.Spec.StorageClassName
-		pvc.Spec.VolumeName = fs.PersistentVolumeName(namespace, mode)
+	return result, nil
+}
 
-		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
-			mode,
+// expireAccesses records when each namespace access was granted and when it expires, and removes the expired
+// namespace accesses from the accesses. The expired accesses are returned along with the time until the next
+// namespace access expires, which is zero when none will.
+func (r *LustreFileSystemReconciler) expireAccesses(fs *lusv1beta1.LustreFileSystem, accesses map[string]lusv1beta1.LustreFileSystemNamespaceSpec) (map[string]lusv1beta1.LustreFileSystemNamespaceSpec, time.Duration) {
+	if fs.Status.Namespaces == nil {
+		fs.Status.Namespaces = make(map[string]lusv1beta1.LustreFileSystemNamespaceStatus)
+	}
+
+	// The times are kept to the second, as they're stored in the status, so an expiry doesn't move once recorded
+	now := metav1.Now().Rfc3339Copy()
+	expired := map[string]lusv1beta1.LustreFileSystemNamespaceSpec{}
+	expiresIn := time.Duration(0)
+	for namespace, spec := range accesses {
+		namespaceStatus := fs.Status.Namespaces[namespace]
+		if namespaceStatus.GrantedAt == nil {
+			namespaceStatus.GrantedAt = &now
 		}
 
-		pvc.Spec.Resources = corev1.ResourceRequirements{
-			Requests: corev1.ResourceList{
-				corev1.ResourceStorage: persistentVolumeResourceQuantity,
-			},
+		namespaceStatus.ExpiresAt = spec.Expiry(*namespaceStatus.GrantedAt)
+		if namespaceStatus.ExpiresAt != nil && namespaceStatus.Modes == nil {
+			namespaceStatus.Modes = make(map[corev1.PersistentVolumeAccessMode]lusv1beta1.LustreFileSystemNamespaceAccessStatus)
 		}
 
-		return nil
-	}
+		fs.Status.Namespaces[namespace] = namespaceStatus
 
-	result, err := ctrl.CreateOrUpdate(ctx, r.Client, pvc, mutateFn)
-	if err != nil {
-		return nil, err
-	}
+		if namespaceStatus.ExpiresAt == nil {
+			continue
+		}
 
-	if result != controllerutil.OperationResultNone {
-		log.FromContext(ctx).Info("PersistentVolumeClaim", "object", client.ObjectKeyFromObject(pvc).String(), "result", result)
+		remaining := namespaceStatus.ExpiresAt.Sub(now.Time)
+		if remaining <= 0 {
+			expired[namespace] = spec
+			delete(accesses, namespace)
+			continue
+		}
+
+		if expiresIn == 0 || remaining < expiresIn {
+			expiresIn = remaining
+		}
 	}
 
-	return pvc, nil
+	return expired, expiresIn
 }
 
-func (r *LustreFileSystemReconciler) createOrUpdatePersistentVolume(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) (*corev1.PersistentVolume, error) {
+// setConditions refreshes the observed generation and the conditions of the file system based
+// on the namespace access status and the error, if any, returned from the reconcile.
+func (r *LustreFileSystemReconciler) setConditions(fs *lusv1beta1.LustreFileSystem, accesses map[string]lusv1beta1.LustreFileSystemNamespaceSpec, err error) {
 
-	pv := &corev1.PersistentVolume{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: fs.PersistentVolumeName(namespace, mode),
-		},
+	// The finalizer was removed and the object is about to go away; there is nothing left to report.
+	if !fs.GetDeletionTimestamp().IsZero() && !controllerutil.ContainsFinalizer(fs, finalizerLustreFileSystem) {
+		return
 	}
 
-	mutateFn := func() error {
-		volumeMode := corev1.PersistentVolumeFilesystem
-		pv.Spec.VolumeMode = &volumeMode
+	fs.Status.ObservedGeneration = fs.Generation
 
-		pv.Spec.StorageClassName = fs.Spec.StorageClassName
-		pv.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
-			corev1.ReadWriteMany,
-		}
+	setCondition := func(conditionType string, status metav1.ConditionStatus, reason, message string) {
+		meta.SetStatusCondition(&fs.Status.Conditions, metav1.Condition{
+			Type:               conditionType,
+			Status:             status,
+			ObservedGeneration: fs.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
 
-		pv.Spec.Capacity = corev1.ResourceList{
-			corev1.ResourceStorage: persistentVolumeResourceQuantity,
-		}
+	// The namespace accesses weren't determined; fall back to those listed in the specification.
+	if accesses == nil {
+		accesses = fs.Spec.Namespaces
+	}
 
-		if pv.Spec.ClaimRef == nil {
-			pv.Spec.ClaimRef = &corev1.ObjectReference{}
-		} else if pv.Status.Phase == corev1.VolumeReleased {
-			// The PV is being updated, and it was bound to a PVC
-			// but now it's released.  Clear the uid of the
-			// earlier PVC from the claimRef.
-			// This allows it to bind to a new PVC.
-			pv.Spec.ClaimRef.UID = ""
+	// Count the namespace accesses in the specification that are missing from the status or not yet ready.
+	reconciled, pending := true, 0
+	for namespace, spec := range accesses {
+		for _, mode := range spec.Modes {
+			access, found := fs.Status.Namespaces[namespace].Modes[mode]
+			if !found {
+				reconciled = false
+			}
+			if access.State != lusv1beta1.NamespaceAccessReady {
+				pending++
+			}
 		}
+	}
 
-		// Reserve this PV for the matching PVC.
-		pv.Spec.ClaimRef.Name = fs.PersistentVolumeClaimName(namespace, mode)
-		pv.Spec.ClaimRef.Namespace = namespace
+	// Any namespace access in the status that is no longer in the specification has yet to be removed. Those
+	// waiting for pods to finish are counted separately so they don't make the file system unready.
+	revoking := 0
+	for namespace, status := range fs.Status.Namespaces {
+		for mode, access := range status.Modes {
+			if slices.Contains(accesses[namespace].Modes, mode) {
+				continue
+			}
 
-		pv.Spec.PersistentVolumeSource = corev1.PersistentVolumeSource{
-			CSI: &corev1.CSIPersistentVolumeSource{
-				Driver:       os.Getenv("LUSTRE_CSI_SERVICE_NAME"),
-				FSType:       "lustre",
-				VolumeHandle: fs.Spec.MgsNids + ":/" + fs.Spec.Name,
-			},
+			if access.State == lusv1beta1.NamespaceAccessExpired {
+				continue
+			}
+
+			if access.State == lusv1beta1.NamespaceAccessRevoking {
+				revoking++
+			} else {
+				reconciled = false
+			}
 		}
+	}
 
-		return nil
+	if !fs.GetDeletionTimestamp().IsZero() && len(fs.Status.DeletionBlockedBy) != 0 {
+		setCondition(lusv1beta1.ConditionDeleting, metav1.ConditionTrue, lusv1beta1.ConditionReasonInUse, fmt.Sprintf("File system deletion is waiting for %d pod(s) using it to finish", len(fs.Status.DeletionBlockedBy)))
+	} else if !fs.GetDeletionTimestamp().IsZero() {
+		setCondition(lusv1beta1.ConditionDeleting, metav1.ConditionTrue, lusv1beta1.ConditionReasonDeleting, "File system is being deleted")
+	} else {
+		setCondition(lusv1beta1.ConditionDeleting, metav1.ConditionFalse, lusv1beta1.ConditionReasonNotDeleting, "")
 	}
 
-	result, err := ctrl.CreateOrUpdate(ctx, r.Client, pv, mutateFn)
-	if err != nil {
-		return nil, err
+	switch {
+	case err != nil:
+		setCondition(lusv1beta1.ConditionNamespacesReconciled, metav1.ConditionFalse, lusv1beta1.ConditionReasonReconcileError, err.Error())
+	case !reconciled:
+		setCondition(lusv1beta1.ConditionNamespacesReconciled, metav1.ConditionFalse, lusv1beta1.ConditionReasonAccessPending, "Namespace accesses do not yet match the specification")
+	case revoking != 0:
+		setCondition(lusv1beta1.ConditionNamespacesReconciled, metav1.ConditionFalse, lusv1beta1.ConditionReasonRevoking, fmt.Sprintf("%d revoked namespace access(es) waiting for pods to finish", revoking))
+	default:
+		setCondition(lusv1beta1.ConditionNamespacesReconciled, metav1.ConditionTrue, lusv1beta1.ConditionReasonReconciled, "")
 	}
 
-	if result != controllerutil.OperationResultNone {
-		log.FromContext(ctx).Info("PersistentVolume", "object", client.ObjectKeyFromObject(pv).String(), "result", result)
+	switch {
+	case err != nil:
+		setCondition(lusv1beta1.ConditionDegraded, metav1.ConditionTrue, lusv1beta1.ConditionReasonReconcileError, err.Error())
+	case pending != 0:
+		setCondition(lusv1beta1.ConditionDegraded, metav1.ConditionTrue, lusv1beta1.ConditionReasonAccessPending, fmt.Sprintf("%d namespace access(es) not ready", pending))
+	default:
+		setCondition(lusv1beta1.ConditionDegraded, metav1.ConditionFalse, lusv1beta1.ConditionReasonReconciled, "")
 	}
 
-	return pv, nil
+	switch {
+	case !fs.GetDeletionTimestamp().IsZero():
+		setCondition(lusv1beta1.ConditionReady, metav1.ConditionFalse, lusv1beta1.ConditionReasonDeleting, "File system is being deleted")
+	case err != nil:
+		setCondition(lusv1beta1.ConditionReady, metav1.ConditionFalse, lusv1beta1.ConditionReasonReconcileError, err.Error())
+	case !reconciled || pending != 0:
+		setCondition(lusv1beta1.ConditionReady, metav1.ConditionFalse, lusv1beta1.ConditionReasonAccessPending, fmt.Sprintf("%d namespace access(es) not ready", pending))
+	default:
+		setCondition(lusv1beta1.ConditionReady, metav1.ConditionTrue, lusv1beta1.ConditionReasonReconciled, "")
+	}
 }
 
-func (r *LustreFileSystemReconciler) deleteAccess(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) error {
-	pvc := &corev1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fs.PersistentVolumeClaimName(namespace, mode),
-			Namespace: namespace,
+// setRolloverStatus records the rollover status of the namespace, keeping the start time of a rollover in progress.
+// The rollover status is cleared when no claim is waiting to move.
+func (r *LustreFileSystemReconciler) setRolloverStatus(fs *lusv1beta1.LustreFileSystem, namespace string, rollover *lusv1beta1.LustreFileSystemNamespaceRolloverStatus) {
+	namespaceStatus, found := fs.Status.Namespaces[namespace]
+	if !found {
+		return
+	}
+
+	if len(rollover.PendingModes) == 0 {
+		if namespaceStatus.Rollover != nil {
+			r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonRolloverComplete, "Namespace '%s' claims moved to export path '%s'", namespace, namespaceStatus.Rollover.ExportPath)
+		}
+
+		namespaceStatus.Rollover = nil
+		fs.Status.Namespaces[namespace] = namespaceStatus
+		return
+	}
+
+	rollover.ExportPath = namespaceStatus.ExportPath
+	slices.Sort(rollover.Pods)
+	rollover.Pods = slices.Compact(rollover.Pods)
+
+	if previous := namespaceStatus.Rollover; previous != nil && previous.StartTime != nil {
+		rollover.StartTime = previous.StartTime
+	} else {
+		now := metav1.Now()
+		rollover.StartTime = &now
+	}
+
+	namespaceStatus.Rollover = rollover
+	fs.Status.Namespaces[namespace] = namespaceStatus
+}
+
+// reconcileQuota sets the project quota of the namespace to the quota in its specification and records the usage
+// of the project. The project ID is allocated the first time, see allocateProjectID, and is assigned to the
+// subdirectory of the namespace.
+// The quota is removed when the specification no longer has one.
+func (r *LustreFileSystemReconciler) reconcileQuota(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, spec lusv1beta1.LustreFileSystemNamespaceSpec) error {
+	if spec.Quota == nil {
+		return r.removeQuota(ctx, fs, namespace)
+	}
+
+	namespaceStatus := fs.Status.Namespaces[namespace]
+	quota := namespaceStatus.Quota
+	if quota == nil {
+		quota = &lusv1beta1.LustreFileSystemNamespaceQuotaStatus{}
+	}
+
+	// The status is recorded even when the quota fails, to keep the project ID allocated
+	setQuotaStatus := func(err error) error {
+		message := ""
+		if err != nil {
+			message = err.Error()
+		}
+
+		if message != quota.Message && len(message) != 0 {
+			r.Recorder.Eventf(fs, corev1.EventTypeWarning, eventReasonQuotaError, "Namespace '%s' quota: %s", namespace, message)
+		}
+
+		quota.Message = message
+		namespaceStatus.Quota = quota
+		fs.Status.Namespaces[namespace] = namespaceStatus
+
+		if err != nil {
+			return &quotaError{namespace: namespace, err: err}
+		}
+
+		return nil
+	}
+
+	// Retrying doesn't help until the operator is configured to manage the project quotas. No project ID is
+	// allocated, so the status only holds the message.
+	if r.Quotas == nil {
+		_ = setQuotaStatus(fmt.Errorf("project quotas are not managed by the operator"))
+		return nil
+	}
+
+	subdirectory, err := fs.NamespaceSubdirectory(namespace, spec)
+	if err != nil {
+		return setQuotaStatus(fmt.Errorf("invalid subdirectory: %w", err))
+	}
+
+	path := filepath.Join(fs.Spec.MountRoot, subdirectory)
+	if quota.ProjectID == 0 {
+		projectID, err := r.allocateProjectID(ctx, fs, path)
+		if err != nil {
+			return setQuotaStatus(err)
+		}
+
+		quota.ProjectID = projectID
+	}
+
+	// Assigning the project ID walks the whole subdirectory, so it's only done when the path changes
+	if quota.Path != path {
+		if err := r.Quotas.SetProject(ctx, path, quota.ProjectID); err != nil {
+			return setQuotaStatus(err)
+		}
+
+		quota.Path = path
+		quota.LastUpdateTime = nil
+	}
+
+	if !equality.Semantic.DeepEqual(quota.Limits, spec.Quota) {
+		if err := r.Quotas.SetQuota(ctx, fs.Spec.MountRoot, quota.ProjectID, quotaLimits(spec.Quota)); err != nil {
+			return setQuotaStatus(err)
+		}
+
+		quota.Limits = spec.Quota.DeepCopy()
+		quota.LastUpdateTime = nil
+		r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonQuotaApplied, "Namespace '%s' quota applied to project %d at '%s'", namespace, quota.ProjectID, path)
+	}
+
+	// The usage is only read every so often, since each update of the status triggers another reconcile
+	if quota.LastUpdateTime == nil || time.Since(quota.LastUpdateTime.Time) >= quotaUsageRefreshInterval {
+		usage, err := r.Quotas.GetQuota(ctx, fs.Spec.MountRoot, quota.ProjectID)
+		if err != nil {
+			return setQuotaStatus(err)
+		}
+
+		now := metav1.Now().Rfc3339Copy()
+		quota.BlockUsage = resource.NewQuantity(usage.BlockBytes, resource.BinarySI)
+		quota.InodeUsage = usage.Inodes
+		quota.LastUpdateTime = &now
+	}
+
+	return setQuotaStatus(nil)
+}
+
+// removeQuota removes the limits of the project quota of the namespace and the project ID from its subdirectory,
+// then clears the quota from the namespace status
+func (r *LustreFileSystemReconciler) removeQuota(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string) error {
+	namespaceStatus, found := fs.Status.Namespaces[namespace]
+	if !found || namespaceStatus.Quota == nil {
+		return nil
+	}
+
+	quota := namespaceStatus.Quota
+	if r.Quotas != nil {
+		if quota.Limits != nil {
+			if err := r.Quotas.SetQuota(ctx, fs.Spec.MountRoot, quota.ProjectID, lfs.Limits{}); err != nil {
+				return &quotaError{namespace: namespace, err: err}
+			}
+
+			quota.Limits = nil
+		}
+
+		if len(quota.Path) != 0 {
+			if err := r.Quotas.ClearProject(ctx, quota.Path); err != nil {
+				return &quotaError{namespace: namespace, err: err}
+			}
+
+			r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonQuotaRemoved, "Namespace '%s' quota removed from project %d at '%s'", namespace, quota.ProjectID, quota.Path)
+		}
+	}
+
+	namespaceStatus.Quota = nil
+	fs.Status.Namespaces[namespace] = namespaceStatus
+
+	return nil
+}
+
+// allocateProjectID returns the project ID for the quota of the namespace subdirectory at the path. The project ID
+// already set on the directory is kept, e.g. when the status was lost or the quota was set up by hand, unless it's
+// allocated to another namespace of the file system. Otherwise it's the lowest project ID, from firstProjectID up,
+// that isn't allocated to a namespace of the file system. The project ID is then assigned with a recursive
+// 'lfs project -s -r', which blocks the worker until it has walked the whole subdirectory; on a large tree that
+// can take a long time, so the runner bounds each command with the lfs timeout of the operator.
+func (r *LustreFileSystemReconciler) allocateProjectID(ctx context.Context, fs *lusv1beta1.LustreFileSystem, path string) (int64, error) {
+	allocated := map[int64]bool{}
+	for _, namespaceStatus := range fs.Status.Namespaces {
+		if namespaceStatus.Quota != nil && namespaceStatus.Quota.ProjectID != 0 {
+			allocated[namespaceStatus.Quota.ProjectID] = true
+		}
+	}
+
+	projectID, err := r.Quotas.GetProject(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	if projectID != 0 && !allocated[projectID] {
+		return projectID, nil
+	}
+
+	projectID = firstProjectID
+	for allocated[projectID] {
+		projectID++
+	}
+
+	return projectID, nil
+}
+
+// quotaLimits returns the limits of the project for the quota
+func quotaLimits(quota *lusv1beta1.LustreFileSystemNamespaceQuota) lfs.Limits {
+	limits := lfs.Limits{}
+	if quota.BlockLimit != nil {
+		limits.BlockBytes = quota.BlockLimit.Value()
+	}
+
+	if quota.InodeLimit != nil {
+		limits.Inodes = *quota.InodeLimit
+	}
+
+	return limits
+}
+
+// getPersistentVolumeName returns the name of the persistent volume for the namespace access with the export path.
+// A claim keeps the persistent volume it is bound to, so one named under an earlier naming scheme stays in use until
+// the claim is recreated. When the claim is bound to a persistent volume with a different export path and the MGS
+// NIDs update policy allows a rollover, the name of a new persistent volume is returned along with the name of the
+// persistent volume the claim is bound to.
+func (r *LustreFileSystemReconciler) getPersistentVolumeName(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode, claimName string, exportPath string) (string, string, error) {
+	name := fs.PersistentVolumeName(namespace, mode)
+	rollover := fs.Spec.MgsNidsUpdatePolicy == lusv1beta1.MgsNidsUpdateRollover
+	rolloverName := fs.PersistentVolumeRolloverName(namespace, mode, exportPath)
+
+	// getVolumeHandle returns the volume handle of the persistent volume, and false if it doesn't exist
+	getVolumeHandle := func(name string) (string, bool, error) {
+		pv := &corev1.PersistentVolume{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name}, pv); err != nil {
+			return "", false, client.IgnoreNotFound(err)
+		}
+
+		if pv.Spec.CSI == nil {
+			return "", true, nil
+		}
+
+		return pv.Spec.CSI.VolumeHandle, true, nil
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: claimName, Namespace: namespace}, pvc); err != nil {
+		if !errors.IsNotFound(err) {
+			return "", "", err
+		}
+	} else if managesClaim(pvc, fs, namespace, mode) && len(pvc.Spec.VolumeName) != 0 {
+		handle, found, err := getVolumeHandle(pvc.Spec.VolumeName)
+		if err != nil {
+			return "", "", err
+		}
+
+		if !found || handle == exportPath || !rollover {
+			return pvc.Spec.VolumeName, "", nil
+		}
+
+		return rolloverName, pvc.Spec.VolumeName, nil
+	}
+
+	if !rollover {
+		return name, "", nil
+	}
+
+	// There is no claim to move, so use a persistent volume that already has the export path, or the
+	// original persistent volume if it is free for the export path
+	if _, found, err := getVolumeHandle(rolloverName); err != nil || found {
+		return rolloverName, "", err
+	}
+
+	handle, found, err := getVolumeHandle(name)
+	if err != nil {
+		return "", "", err
+	}
+
+	if !found || handle == exportPath {
+		return name, "", nil
+	}
+
+	return rolloverName, "", nil
+}
+
+// getPodsUsingClaims returns the names of the pods in the namespace that use one of the persistent volume claims and
+// haven't finished
+func (r *LustreFileSystemReconciler) getPodsUsingClaims(ctx context.Context, namespace string, claimNames ...string) ([]string, error) {
+	names := []string{}
+	if len(claimNames) == 0 {
+		return names, nil
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && slices.Contains(claimNames, volume.PersistentVolumeClaim.ClaimName) {
+				names = append(names, namespace+"/"+pod.Name)
+				break
+			}
+		}
+	}
+
+	return names, nil
+}
+
+// deletePersistentVolumeClaimForRollover deletes a persistent volume claim that no pod is using so it can be
+// recreated bound to a new persistent volume
+func (r *LustreFileSystemReconciler) deletePersistentVolumeClaimForRollover(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, claimName string) error {
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: claimName, Namespace: namespace}, pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !pvc.DeletionTimestamp.IsZero() {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Deleting PersistentVolumeClaim for rollover", "object", client.ObjectKeyFromObject(pvc).String())
+	if err := r.Delete(ctx, pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	metrics.PersistentVolumeClaimOperationsTotal.WithLabelValues(metrics.OperationDelete).Inc()
+	r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonRolloverPending, "PersistentVolumeClaim %s deleted to move it to a persistent volume with export path '%s'", client.ObjectKeyFromObject(pvc), fs.Status.Namespaces[namespace].ExportPath)
+
+	return nil
+}
+
+// getAccessPersistentVolumes returns the persistent volumes of the file system that are reserved for the named claim
+// of a namespace access, including those with a previous export path
+func (r *LustreFileSystemReconciler) getAccessPersistentVolumes(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, claimName string) ([]corev1.PersistentVolume, error) {
+	pvs := &corev1.PersistentVolumeList{}
+	if err := r.List(ctx, pvs, client.MatchingLabels{lusv1beta1.OwnerNameLabel: fs.Name, lusv1beta1.OwnerNamespaceLabel: fs.Namespace}); err != nil {
+		return nil, err
+	}
+
+	accessPVs := []corev1.PersistentVolume{}
+	for _, pv := range pvs.Items {
+		if claimRef := pv.Spec.ClaimRef; claimRef != nil && claimRef.Name == claimName && claimRef.Namespace == namespace {
+			accessPVs = append(accessPVs, pv)
+		}
+	}
+
+	return accessPVs, nil
+}
+
+// deletePreviousPersistentVolumes deletes the persistent volumes of the namespace access other than the named one
+func (r *LustreFileSystemReconciler) deletePreviousPersistentVolumes(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, claimName string, name string) error {
+	pvs, err := r.getAccessPersistentVolumes(ctx, fs, namespace, claimName)
+	if err != nil {
+		return err
+	}
+
+	for i := range pvs {
+		pv := &pvs[i]
+		if pv.Name == name || !pv.DeletionTimestamp.IsZero() || pv.Status.Phase == corev1.VolumeBound {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Deleting previous PersistentVolume", "object", client.ObjectKeyFromObject(pv).String())
+		if err := r.Delete(ctx, pv); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			continue
+		}
+
+		metrics.PersistentVolumeOperationsTotal.WithLabelValues(metrics.OperationDelete).Inc()
+		r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonPersistentVolumeDeleted, "PersistentVolume %s with a previous export path or name deleted", pv.Name)
+	}
+
+	return nil
+}
+
+func (r *LustreFileSystemReconciler) createOrUpdatePersistentVolumeClaim(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, namespaceSpec lusv1beta1.LustreFileSystemNamespaceSpec, mode corev1.PersistentVolumeAccessMode, claimName string, pvName string) (*corev1.PersistentVolumeClaim, error) {
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      claimName,
+			Namespace: namespace,
 		},
 	}
 
-	log.FromContext(ctx).Info("Deleting PersistentVolumeClaim", "object", client.ObjectKeyFromObject(pvc).String())
-	if err := r.Delete(ctx, pvc); err != nil {
+	mutateFn := func() error {
+		// Don't take over a claim created by someone else with the same name
+		if len(pvc.ResourceVersion) != 0 && !managesClaim(pvc, fs, namespace, mode) {
+			return &persistentVolumeClaimConflictError{name: claimName}
+		}
+
+		if err := setAccessMetadata(pvc, fs, namespace, namespaceSpec, mode); err != nil {
+			return err
+		}
+
+		setOwnerLabels(pvc, fs)
+
+		pvc.Spec.StorageClassName = &fs.Spec.StorageClassName
+		pvc.Spec.VolumeName = pvName
+
+		pvc.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
+			mode,
+		}
+
+		pvc.Spec.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{
+				corev1.ResourceStorage: persistentVolumeResourceQuantity,
+			},
+		}
+
+		return nil
+	}
+
+	result, err := ctrl.CreateOrUpdate(ctx, r.Client, pvc, mutateFn)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("PersistentVolumeClaim", "object", client.ObjectKeyFromObject(pvc).String(), "result", result)
+		metrics.PersistentVolumeClaimOperationsTotal.WithLabelValues(metricsOperationForResult(result)).Inc()
+		r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonForResult("PersistentVolumeClaim", result), "PersistentVolumeClaim %s %s", client.ObjectKeyFromObject(pvc), result)
+	}
+
+	return pvc, nil
+}
+
+func (r *LustreFileSystemReconciler) createOrUpdatePersistentVolume(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, namespaceSpec lusv1beta1.LustreFileSystemNamespaceSpec, mode corev1.PersistentVolumeAccessMode, claimName string, pvName string, exportPath string) (*corev1.PersistentVolume, error) {
+
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: pvName,
+		},
+	}
+
+	// File systems created before the CSI driver was part of the specification use the operator's default
+	driver := fs.Spec.CSIDriver
+	if len(driver) == 0 {
+		driver = lusv1beta1.GetDefaults().CSIDriver
+	}
+
+	if len(driver) == 0 {
+		return nil, fmt.Errorf("no CSI driver specified for file system '%s' and the operator has no default CSI driver", client.ObjectKeyFromObject(fs))
+	}
+
+	mutateFn := func() error {
+		// Don't take over a PV of another file system, whatever its phase
+		if isOwnedByOther(pv, fs) {
+			return &persistentVolumeConflictError{name: pv.Name, owner: pv.Labels[lusv1beta1.OwnerNamespaceLabel] + "/" + pv.Labels[lusv1beta1.OwnerNameLabel]}
+		}
+
+		// Don't take over a PV that is bound to some other claim
+		if claimRef := pv.Spec.ClaimRef; claimRef != nil && pv.Status.Phase == corev1.VolumeBound {
+			if claimRef.Name != claimName || claimRef.Namespace != namespace {
+				return &persistentVolumeConflictError{name: pv.Name, claim: claimRef.Namespace + "/" + claimRef.Name}
+			}
+		}
+
+		if err := setAccessMetadata(pv, fs, namespace, namespaceSpec, mode); err != nil {
+			return err
+		}
+
+		setOwnerLabels(pv, fs)
+
+		volumeMode := corev1.PersistentVolumeFilesystem
+		pv.Spec.VolumeMode = &volumeMode
+
+		pv.Spec.StorageClassName = fs.Spec.StorageClassName
+		pv.Spec.AccessModes = []corev1.PersistentVolumeAccessMode{
+			mode,
+		}
+
+		pv.Spec.Capacity = corev1.ResourceList{
+			corev1.ResourceStorage: persistentVolumeResourceQuantity,
+		}
+
+		if pv.Spec.ClaimRef == nil {
+			pv.Spec.ClaimRef = &corev1.ObjectReference{}
+		} else if pv.Status.Phase == corev1.VolumeReleased {
+			// The PV is being updated, and it was bound to a PVC
+			// but now it's released.  Clear the uid of the
+			// earlier PVC from the claimRef.
+			// This allows it to bind to a new PVC.
+			pv.Spec.ClaimRef.UID = ""
+		}
+
+		// Reserve this PV for the matching PVC.
+		pv.Spec.ClaimRef.Name = claimName
+		pv.Spec.ClaimRef.Namespace = namespace
+
+		source := corev1.PersistentVolumeSource{
+			CSI: &corev1.CSIPersistentVolumeSource{
+				Driver:           driver,
+				FSType:           "lustre",
+				VolumeHandle:     exportPath,
+				ReadOnly:         mode == corev1.ReadOnlyMany,
+				VolumeAttributes: fs.NamespaceVolumeAttributes(namespaceSpec),
+			},
+		}
+
+		// A PV created before the read only flag was set keeps its flag, since the volume source can't be
+		// changed while the PV is bound. The 'ro' mount option still makes the mount read only.
+		if existing := pv.Spec.PersistentVolumeSource.CSI; !pv.CreationTimestamp.IsZero() && existing != nil {
+			source.CSI.ReadOnly = existing.ReadOnly
+		}
+
+		// The volume source can't be changed once the PV exists
+		if !pv.CreationTimestamp.IsZero() && !equality.Semantic.DeepEqual(pv.Spec.PersistentVolumeSource, source) {
+			return &persistentVolumeSourceChangedError{name: pv.Name}
+		}
+
+		pv.Spec.PersistentVolumeSource = source
+		pv.Spec.MountOptions = accessMountOptions(fs.NamespaceMountOptions(namespaceSpec), mode)
+
+		return nil
+	}
+
+	result, err := ctrl.CreateOrUpdate(ctx, r.Client, pv, mutateFn)
+	if err != nil {
+		if _, changed := err.(*persistentVolumeSourceChangedError); changed && pv.Status.Phase != corev1.VolumeBound && pv.DeletionTimestamp.IsZero() {
+			// Delete the PV so it is recreated with the correct volume source once it is gone
+			if err := r.Delete(ctx, pv); err != nil && !errors.IsNotFound(err) {
+				return nil, err
+			}
+
+			log.FromContext(ctx).Info("PersistentVolume", "object", client.ObjectKeyFromObject(pv).String(), "result", "deleted for recreate")
+			metrics.PersistentVolumeOperationsTotal.WithLabelValues(metrics.OperationDelete).Inc()
+			r.Recorder.Eventf(fs, corev1.EventTypeWarning, eventReasonPersistentVolumeDeleted, "PersistentVolume %s deleted to be recreated with the correct volume source", pv.Name)
+		}
+
+		return nil, err
+	}
+
+	if result != controllerutil.OperationResultNone {
+		log.FromContext(ctx).Info("PersistentVolume", "object", client.ObjectKeyFromObject(pv).String(), "result", result)
+		metrics.PersistentVolumeOperationsTotal.WithLabelValues(metricsOperationForResult(result)).Inc()
+		r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonForResult("PersistentVolume", result), "PersistentVolume %s %s", pv.Name, result)
+	}
+
+	return pv, nil
+}
+
+// accessMountOptions returns the mount options for an access with the mode. A ReadOnlyMany access is always
+// mounted read only, replacing any 'rw' option with 'ro'.
+func accessMountOptions(options []string, mode corev1.PersistentVolumeAccessMode) []string {
+	if mode != corev1.ReadOnlyMany {
+		return options
+	}
+
+	readOnly := make([]string, 0, len(options)+1)
+	for _, option := range options {
+		if option != "rw" && option != "ro" {
+			readOnly = append(readOnly, option)
+		}
+	}
+
+	return append(readOnly, "ro")
+}
+
+// setAccessStatus records the status of the namespace access. The last transition time is carried
+// over from the previous status when the state is unchanged. An event is recorded against the file
+// system when the state changes, in which case true is returned.
+func (r *LustreFileSystemReconciler) setAccessStatus(fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode, access lusv1beta1.LustreFileSystemNamespaceAccessStatus) bool {
+	previous, found := fs.Status.Namespaces[namespace].Modes[mode]
+	transitioned := !found || previous.State != access.State || previous.LastTransitionTime == nil
+
+	if transitioned {
+		now := metav1.Now()
+		access.LastTransitionTime = &now
+
+		// Observe how long the access took to become ready, measured from the start of its previous state
+		if access.State == lusv1beta1.NamespaceAccessReady {
+			start := now
+			if found && previous.LastTransitionTime != nil {
+				start = *previous.LastTransitionTime
+			}
+			metrics.NamespaceAccessReadySeconds.Observe(now.Sub(start.Time).Seconds())
+		}
+	} else {
+		access.LastTransitionTime = previous.LastTransitionTime
+	}
+
+	fs.Status.Namespaces[namespace].Modes[mode] = access
+
+	if transitioned {
+		eventType, reason := corev1.EventTypeWarning, ""
+		switch access.State {
+		case lusv1beta1.NamespaceAccessReady:
+			eventType, reason = corev1.EventTypeNormal, eventReasonAccessGranted
+		case lusv1beta1.NamespaceAccessNamespaceNotFound:
+			reason = eventReasonNamespaceNotFound
+		case lusv1beta1.NamespaceAccessNamespaceTerminating:
+			reason = eventReasonNamespaceTerminating
+		case lusv1beta1.NamespaceAccessPVConflict:
+			reason = eventReasonPVConflict
+		case lusv1beta1.NamespaceAccessPVCConflict:
+			reason = eventReasonPVCConflict
+		case lusv1beta1.NamespaceAccessPVCBindFailed:
+			reason = eventReasonPVCBindFailed
+		case lusv1beta1.NamespaceAccessRolloverPending:
+			eventType, reason = corev1.EventTypeNormal, eventReasonRolloverPending
+		case lusv1beta1.NamespaceAccessRevoking:
+			eventType, reason = corev1.EventTypeNormal, eventReasonAccessRevoking
+		case lusv1beta1.NamespaceAccessExpired:
+			eventType, reason = corev1.EventTypeNormal, eventReasonAccessExpired
+		case lusv1beta1.NamespaceAccessError:
+			reason = eventReasonAccessError
+		}
+
+		if reason != "" {
+			message := fmt.Sprintf("Namespace '%s' access %s is %s", namespace, mode, access.State)
+			if access.Message != "" {
+				message += ": " + access.Message
+			}
+			r.Recorder.Event(fs, eventType, reason, message)
+		}
+	}
+
+	return transitioned
+}
+
+// eventReasonForResult returns the event reason for a create or update of the kind of object
+func eventReasonForResult(kind string, result controllerutil.OperationResult) string {
+	switch result {
+	case controllerutil.OperationResultCreated:
+		return kind + "Created"
+	case controllerutil.OperationResultUpdated, controllerutil.OperationResultUpdatedStatus, controllerutil.OperationResultUpdatedStatusOnly:
+		return kind + "Updated"
+	}
+
+	return kind + "Unchanged"
+}
+
+// metricsOperationForResult returns the operation label used in the metrics for the result of a create or update
+func metricsOperationForResult(result controllerutil.OperationResult) string {
+	if result == controllerutil.OperationResultCreated {
+		return metrics.OperationCreate
+	}
+
+	return metrics.OperationUpdate
+}
+
+// revokeAccess revokes a namespace access that is no longer in the specification. The persistent volume and claim
+// are no longer updated, and while pods are using the claim the access is left in the revoking state until they
+// finish or the grace period expires. True is returned once the claim and persistent volume are deleted.
+func (r *LustreFileSystemReconciler) revokeAccess(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) (bool, error) {
+	gracePeriod := fs.RevocationGracePeriod()
+
+	var pods []string
+	if gracePeriod > 0 {
+		var err error
+		if pods, err = r.getPodsUsingClaims(ctx, namespace, accessClaimName(fs, namespace, mode)); err != nil {
+			return false, err
+		}
+	}
+
+	if len(pods) != 0 {
+		access := fs.Status.Namespaces[namespace].Modes[mode]
+		access.State = lusv1beta1.NamespaceAccessRevoking
+		access.Message = fmt.Sprintf("waiting up to %s for %d pod(s) using the claim to finish", gracePeriod, len(pods))
+		access.Pods = pods
+		r.setAccessStatus(fs, namespace, mode, access)
+
+		// Revoke the access anyway once the grace period has expired
+		access = fs.Status.Namespaces[namespace].Modes[mode]
+		if time.Since(access.LastTransitionTime.Time) < gracePeriod {
+			return false, nil
+		}
+
+		log.FromContext(ctx).Info("Revocation grace period expired", "namespace", namespace, "mode", mode, "pods", pods)
+		r.Recorder.Eventf(fs, corev1.EventTypeWarning, eventReasonAccessRevoked, "Namespace '%s' access %s revoked after the grace period of %s with %d pod(s) still using it", namespace, mode, gracePeriod, len(pods))
+	}
+
+	if err := r.deleteAccess(ctx, fs, namespace, mode); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *LustreFileSystemReconciler) deleteAccess(ctx context.Context, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) error {
+	claimName := accessClaimName(fs, namespace, mode)
+
+	// Only delete the claim when it's managed by the file system, since a claim name chosen in the specification
+	// may belong to someone else
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := r.Get(ctx, types.NamespacedName{Name: claimName, Namespace: namespace}, pvc); err != nil {
 		if !errors.IsNotFound(err) {
 			return err
 		}
+	} else if managesClaim(pvc, fs, namespace, mode) {
+		log.FromContext(ctx).Info("Deleting PersistentVolumeClaim", "object", client.ObjectKeyFromObject(pvc).String())
+		if err := r.Delete(ctx, pvc); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+		} else {
+			metrics.PersistentVolumeClaimOperationsTotal.WithLabelValues(metrics.OperationDelete).Inc()
+			r.Recorder.Eventf(pvc, corev1.EventTypeNormal, eventReasonAccessRevoked, "Access to Lustre file system '%s' revoked by %s", fs.Spec.Name, client.ObjectKeyFromObject(fs))
+			r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonPersistentVolumeClaimDeleted, "PersistentVolumeClaim %s deleted", client.ObjectKeyFromObject(pvc))
+		}
 	}
 
-	pv := &corev1.PersistentVolume{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: fs.PersistentVolumeName(namespace, mode),
-		},
+	// Delete the persistent volume along with any created for a rollover of the claim. A persistent volume of another
+	// file system with the same name is left alone.
+	pvNames := []string{}
+	accessPV := &corev1.PersistentVolume{}
+	if err := r.Get(ctx, types.NamespacedName{Name: fs.PersistentVolumeName(namespace, mode)}, accessPV); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+	} else if !isOwnedByOther(accessPV, fs) {
+		pvNames = append(pvNames, accessPV.Name)
 	}
 
-	log.FromContext(ctx).Info("Deleting PersistentVolume", "object", client.ObjectKeyFromObject(pv).String())
-	if err := r.Delete(ctx, pv); err != nil {
+	// A persistent volume named under the earlier naming scheme may predate the owner labels. Its name isn't unique to
+	// the file system, so it's only deleted when it's reserved for the claim of this access.
+	legacyPV := &corev1.PersistentVolume{}
+	if err := r.Get(ctx, types.NamespacedName{Name: fs.LegacyPersistentVolumeName(namespace, mode)}, legacyPV); err != nil {
 		if !errors.IsNotFound(err) {
 			return err
 		}
+	} else if claimRef := legacyPV.Spec.ClaimRef; claimRef != nil && claimRef.Name == claimName && claimRef.Namespace == namespace {
+		pvNames = append(pvNames, legacyPV.Name)
+	}
+
+	pvs, err := r.getAccessPersistentVolumes(ctx, fs, namespace, claimName)
+	if err != nil {
+		return err
+	}
+
+	for _, pv := range pvs {
+		if !slices.Contains(pvNames, pv.Name) {
+			pvNames = append(pvNames, pv.Name)
+		}
+	}
+
+	for _, pvName := range pvNames {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: pvName,
+			},
+		}
+
+		log.FromContext(ctx).Info("Deleting PersistentVolume", "object", client.ObjectKeyFromObject(pv).String())
+		if err := r.Delete(ctx, pv); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+		} else {
+			metrics.PersistentVolumeOperationsTotal.WithLabelValues(metrics.OperationDelete).Inc()
+			r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonPersistentVolumeDeleted, "PersistentVolume %s deleted", pv.Name)
+		}
 	}
 
 	return nil
@@ -365,12 +1649,7 @@ func (r *LustreFileSystemReconciler) deleteAccess(ctx context.Context, fs *lusv1
 func (r *LustreFileSystemReconciler) getLustreFileSystemsHandler(ctx context.Context, o client.Object) []reconcile.Request {
 	var res []reconcile.Request
 
-	filesystems := &lusv1beta1.LustreFileSystemList{}
-	if err := r.List(ctx, filesystems); err != nil && !meta.IsNoMatchError(err) {
-		return res
-	}
-
-	for _, lustre := range filesystems.Items {
+	enqueue := func(lustre *lusv1beta1.LustreFileSystem) {
 		res = append(res, reconcile.Request{
 			NamespacedName: types.NamespacedName{
 				Name:      lustre.GetName(),
@@ -379,16 +1658,520 @@ func (r *LustreFileSystemReconciler) getLustreFileSystemsHandler(ctx context.Con
 		})
 	}
 
+	// Only enqueue the file systems that reference the namespace
+	filesystems := &lusv1beta1.LustreFileSystemList{}
+	if err := r.List(ctx, filesystems, client.MatchingFields{namespacesIndexField: o.GetName()}); err != nil && !meta.IsNoMatchError(err) {
+		return res
+	}
+
+	for i := range filesystems.Items {
+		enqueue(&filesystems.Items[i])
+	}
+
+	// File systems with a namespace selector are enqueued when the selector matches the namespace, or when the
+	// namespace has access and may no longer match the selector
+	filesystems = &lusv1beta1.LustreFileSystemList{}
+	if err := r.List(ctx, filesystems, client.MatchingFields{namespaceSelectorIndexField: "true"}); err != nil && !meta.IsNoMatchError(err) {
+		return res
+	}
+
+	for i := range filesystems.Items {
+		lustre := &filesystems.Items[i]
+
+		if _, found := lustre.Spec.Namespaces[o.GetName()]; found {
+			continue // Already enqueued above
+		}
+
+		if _, found := lustre.Status.Namespaces[o.GetName()]; found {
+			enqueue(lustre)
+			continue
+		}
+
+		selector, err := lustre.Spec.NamespaceSelector.AsSelector()
+		if err != nil {
+			continue
+		}
+
+		if selector.Matches(labels.Set(o.GetLabels())) {
+			enqueue(lustre)
+		}
+	}
+
 	return res
 }
 
+// indexNamespaces returns the namespaces referenced by a LustreFileSystem for the namespaces field index
+func indexNamespaces(o client.Object) []string {
+	fs := o.(*lusv1beta1.LustreFileSystem)
+
+	namespaces := make([]string, 0, len(fs.Spec.Namespaces))
+	for namespace := range fs.Spec.Namespaces {
+		namespaces = append(namespaces, namespace)
+	}
+
+	return namespaces
+}
+
+// indexNamespaceSelector returns "true" for the namespace selector field index when a LustreFileSystem has
+// a namespace selector
+func indexNamespaceSelector(o client.Object) []string {
+	fs := o.(*lusv1beta1.LustreFileSystem)
+	if fs.Spec.NamespaceSelector == nil {
+		return nil
+	}
+
+	return []string{"true"}
+}
+
+// namespaceChangedPredicate filters out namespace updates that can't affect a namespace access. Only
+// changes to the phase, labels, or deletion timestamp of a namespace are of interest.
+func namespaceChangedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldNamespace, ok := e.ObjectOld.(*corev1.Namespace)
+			if !ok {
+				return true
+			}
+
+			newNamespace, ok := e.ObjectNew.(*corev1.Namespace)
+			if !ok {
+				return true
+			}
+
+			return oldNamespace.Status.Phase != newNamespace.Status.Phase ||
+				!oldNamespace.DeletionTimestamp.Equal(newNamespace.DeletionTimestamp) ||
+				!labels.Equals(oldNamespace.GetLabels(), newNamespace.GetLabels())
+		},
+	}
+}
+
+// getNamespaceAccesses returns the namespaces granted access to the file system and their modes. The namespaces
+// listed in the specification are merged with the namespaces matching the namespace selector and the namespaces
+// with an approved access request; the second map holds the namespaces that have access only because they match
+// the selector.
+func (r *LustreFileSystemReconciler) getNamespaceAccesses(ctx context.Context, fs *lusv1beta1.LustreFileSystem) (map[string]lusv1beta1.LustreFileSystemNamespaceSpec, map[string]bool, error) {
+	accesses := make(map[string]lusv1beta1.LustreFileSystemNamespaceSpec, len(fs.Spec.Namespaces))
+	for namespace, spec := range fs.Spec.Namespaces {
+		accesses[namespace] = spec
+	}
+
+	selected := map[string]bool{}
+	if fs.Spec.NamespaceSelector != nil {
+		selector, err := fs.Spec.NamespaceSelector.AsSelector()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		namespaces := &corev1.NamespaceList{}
+		if err := r.List(ctx, namespaces, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, nil, err
+		}
+
+		for _, ns := range namespaces.Items {
+			// Namespaces listed in the specification take precedence over the selector
+			if _, found := accesses[ns.Name]; found {
+				continue
+			}
+
+			if r.IgnoredNamespaces.Ignored(&ns) {
+				continue
+			}
+
+			accesses[ns.Name] = fs.Spec.NamespaceSelector.NamespaceSpec()
+			selected[ns.Name] = true
+		}
+	}
+
+	requests, err := r.getApprovedAccessRequests(ctx, fs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, request := range requests {
+		spec, found := accesses[request.Namespace]
+
+		// A grant that names its claim can't hold a second claim for the requested mode, so it takes precedence.
+		// The LustreFileSystemAccess controller reports the conflict in the status of the request.
+		if found && len(spec.ClaimName) != 0 {
+			continue
+		}
+
+		if slices.Contains(spec.Modes, request.Spec.Mode) {
+			continue
+		}
+
+		spec.Modes = append(slices.Clone(spec.Modes), request.Spec.Mode)
+		accesses[request.Namespace] = spec
+		delete(selected, request.Namespace)
+	}
+
+	return accesses, selected, nil
+}
+
+// getApprovedAccessRequests returns the access requests for the file system that are approved and still allowed
+// by its access policy. A request that's no longer allowed is dropped, which revokes the access it was granted.
+func (r *LustreFileSystemReconciler) getApprovedAccessRequests(ctx context.Context, fs *lusv1beta1.LustreFileSystem) ([]lusv1beta1.LustreFileSystemAccess, error) {
+	requests := &lusv1beta1.LustreFileSystemAccessList{}
+	if err := r.List(ctx, requests, client.MatchingFields{fileSystemRefIndexField: fs.Namespace + "/" + fs.Name}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	approved := []lusv1beta1.LustreFileSystemAccess{}
+	for _, request := range requests.Items {
+		if !request.Approved() || !request.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+
+		ns := &corev1.Namespace{}
+		if err := r.Get(ctx, types.NamespacedName{Name: request.Namespace}, ns); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		if r.IgnoredNamespaces.Ignored(ns) || fs.AccessAllowed(ns, request.Spec.Mode) != nil {
+			continue
+		}
+
+		approved = append(approved, request)
+	}
+
+	return approved, nil
+}
+
+// indexFileSystemRef returns the "namespace/name" of the LustreFileSystem referenced by an access request for
+// the file system reference field index
+func indexFileSystemRef(o client.Object) []string {
+	request := o.(*lusv1beta1.LustreFileSystemAccess)
+
+	return []string{request.Spec.FileSystemRef.Namespace + "/" + request.Spec.FileSystemRef.Name}
+}
+
+// getAccessRequestHandler maps an access request to the LustreFileSystem it references
+func (r *LustreFileSystemReconciler) getAccessRequestHandler(ctx context.Context, o client.Object) []reconcile.Request {
+	request := o.(*lusv1beta1.LustreFileSystemAccess)
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      request.Spec.FileSystemRef.Name,
+		Namespace: request.Spec.FileSystemRef.Namespace,
+	}}}
+}
+
+// reconcileStorageClass checks the storage class of the file system, creating it when it's managed by the
+// operator, and records the result in the StorageClassReady condition. A missing or unsuitable storage class
+// doesn't stop the namespace accesses from being granted; the condition reports it before a claim fails to bind.
+func (r *LustreFileSystemReconciler) reconcileStorageClass(ctx context.Context, fs *lusv1beta1.LustreFileSystem) error {
+	setCondition := func(status metav1.ConditionStatus, reason, message string) {
+		previous := meta.FindStatusCondition(fs.Status.Conditions, lusv1beta1.ConditionStorageClassReady)
+		if status == metav1.ConditionFalse && (previous == nil || previous.Status != status || previous.Reason != reason) {
+			r.Recorder.Event(fs, corev1.EventTypeWarning, eventReasonStorageClassUnavailable, message)
+		}
+
+		meta.SetStatusCondition(&fs.Status.Conditions, metav1.Condition{
+			Type:               lusv1beta1.ConditionStorageClassReady,
+			Status:             status,
+			ObservedGeneration: fs.Generation,
+			Reason:             reason,
+			Message:            message,
+		})
+	}
+
+	if len(fs.Spec.StorageClassName) == 0 {
+		setCondition(metav1.ConditionTrue, lusv1beta1.ConditionReasonStorageClassAvailable, "Persistent volumes have no storage class")
+		return nil
+	}
+
+	driver := fs.Spec.CSIDriver
+	if len(driver) == 0 {
+		driver = lusv1beta1.GetDefaults().CSIDriver
+	}
+
+	sc := &storagev1.StorageClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: fs.Spec.StorageClassName}, sc); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+
+		if fs.Spec.StorageClassPolicy != lusv1beta1.StorageClassManaged {
+			setCondition(metav1.ConditionFalse, lusv1beta1.ConditionReasonStorageClassNotFound, fmt.Sprintf("storage class '%s' does not exist", fs.Spec.StorageClassName))
+			return nil
+		}
+
+		// The persistent volumes are created by the operator, so they're retained and bound as soon as they exist
+		reclaimPolicy := corev1.PersistentVolumeReclaimRetain
+		bindingMode := storagev1.VolumeBindingImmediate
+		sc = &storagev1.StorageClass{
+			ObjectMeta:        metav1.ObjectMeta{Name: fs.Spec.StorageClassName},
+			Provisioner:       driver,
+			ReclaimPolicy:     &reclaimPolicy,
+			VolumeBindingMode: &bindingMode,
+		}
+		setOwnerLabels(sc, fs)
+
+		if err := r.Create(ctx, sc); err != nil {
+			return err
+		}
+
+		r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonStorageClassCreated, "Storage class '%s' created", sc.Name)
+	}
+
+	owned := isOwnedBy(sc, fs)
+	if fs.Spec.StorageClassPolicy == lusv1beta1.StorageClassManaged && !owned {
+		setCondition(metav1.ConditionFalse, lusv1beta1.ConditionReasonStorageClassConflict, fmt.Sprintf("storage class '%s' exists and is not managed by this file system", sc.Name))
+		return nil
+	}
+
+	// The storage class is no longer managed by this file system, so release it rather than deleting it with the file system
+	if fs.Spec.StorageClassPolicy != lusv1beta1.StorageClassManaged && owned {
+		delete(sc.Labels, lusv1beta1.OwnerNameLabel)
+		delete(sc.Labels, lusv1beta1.OwnerNamespaceLabel)
+		if err := r.Update(ctx, sc); err != nil {
+			return err
+		}
+	}
+
+	if sc.Provisioner != driver && sc.Provisioner != noProvisioner {
+		setCondition(metav1.ConditionFalse, lusv1beta1.ConditionReasonStorageClassMismatch, fmt.Sprintf("storage class '%s' has provisioner '%s'; expected '%s' or '%s'", sc.Name, sc.Provisioner, driver, noProvisioner))
+		return nil
+	}
+
+	if sc.VolumeBindingMode != nil && *sc.VolumeBindingMode != storagev1.VolumeBindingImmediate {
+		setCondition(metav1.ConditionFalse, lusv1beta1.ConditionReasonStorageClassMismatch, fmt.Sprintf("storage class '%s' has volume binding mode '%s'; expected '%s'", sc.Name, *sc.VolumeBindingMode, storagev1.VolumeBindingImmediate))
+		return nil
+	}
+
+	setCondition(metav1.ConditionTrue, lusv1beta1.ConditionReasonStorageClassAvailable, "")
+	return nil
+}
+
+// deleteStorageClass deletes the storage class of the file system when it's managed by the file system
+func (r *LustreFileSystemReconciler) deleteStorageClass(ctx context.Context, fs *lusv1beta1.LustreFileSystem) error {
+	if fs.Spec.StorageClassPolicy != lusv1beta1.StorageClassManaged || len(fs.Spec.StorageClassName) == 0 {
+		return nil
+	}
+
+	sc := &storagev1.StorageClass{}
+	if err := r.Get(ctx, types.NamespacedName{Name: fs.Spec.StorageClassName}, sc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	if !isOwnedBy(sc, fs) {
+		return nil
+	}
+
+	if err := r.Delete(ctx, sc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+
+	r.Recorder.Eventf(fs, corev1.EventTypeNormal, eventReasonStorageClassDeleted, "Storage class '%s' deleted", sc.Name)
+	return nil
+}
+
+// indexStorageClass returns the storage class of a LustreFileSystem for the storage class field index
+func indexStorageClass(o client.Object) []string {
+	fs := o.(*lusv1beta1.LustreFileSystem)
+	if len(fs.Spec.StorageClassName) == 0 {
+		return nil
+	}
+
+	return []string{fs.Spec.StorageClassName}
+}
+
+// getStorageClassHandler maps a storage class to the LustreFileSystems that use it
+func (r *LustreFileSystemReconciler) getStorageClassHandler(ctx context.Context, o client.Object) []reconcile.Request {
+	filesystems := &lusv1beta1.LustreFileSystemList{}
+	if err := r.List(ctx, filesystems, client.MatchingFields{storageClassIndexField: o.GetName()}); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(filesystems.Items))
+	for i := range filesystems.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&filesystems.Items[i])})
+	}
+
+	return requests
+}
+
+// setOwnerLabels labels a persistent volume or claim with the LustreFileSystem that owns it. The labels
+// filter the manager's cache and map changes to the object back to the LustreFileSystem.
+func setOwnerLabels(obj client.Object, fs *lusv1beta1.LustreFileSystem) {
+	ownerLabels := obj.GetLabels()
+	if ownerLabels == nil {
+		ownerLabels = map[string]string{}
+	}
+
+	ownerLabels[lusv1beta1.OwnerNameLabel] = fs.Name
+	ownerLabels[lusv1beta1.OwnerNamespaceLabel] = fs.Namespace
+	obj.SetLabels(ownerLabels)
+}
+
+// isOwnedBy returns true when the object has the owner labels of the file system
+func isOwnedBy(obj client.Object, fs *lusv1beta1.LustreFileSystem) bool {
+	ownerLabels := obj.GetLabels()
+	return ownerLabels[lusv1beta1.OwnerNameLabel] == fs.Name && ownerLabels[lusv1beta1.OwnerNamespaceLabel] == fs.Namespace
+}
+
+// isOwnedByOther returns true when the object has the owner labels of another file system. An object without owner
+// labels predates them and isn't owned by another file system.
+func isOwnedByOther(obj client.Object, fs *lusv1beta1.LustreFileSystem) bool {
+	_, labeled := obj.GetLabels()[lusv1beta1.OwnerNameLabel]
+	return labeled && !isOwnedBy(obj, fs)
+}
+
+// managesClaim returns true when the claim is managed by the file system. A claim with the generated name and no
+// owner labels predates the labels and is managed by the file system.
+func managesClaim(pvc *corev1.PersistentVolumeClaim, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) bool {
+	if _, labeled := pvc.Labels[lusv1beta1.OwnerNameLabel]; !labeled {
+		return pvc.Name == fs.PersistentVolumeClaimName(namespace, mode)
+	}
+
+	return isOwnedBy(pvc, fs)
+}
+
+// accessClaimName returns the name of the claim of a namespace access recorded in the status, or the generated name
+// when the status doesn't have one. The namespace specification may be gone when the access is revoked, so the
+// recorded name is used to find the claim.
+func accessClaimName(fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode) string {
+	if pvcRef := fs.Status.Namespaces[namespace].Modes[mode].PersistentVolumeClaimRef; pvcRef != nil {
+		return pvcRef.Name
+	}
+
+	return fs.PersistentVolumeClaimName(namespace, mode)
+}
+
+// setAccessMetadata adds the labels and annotations of the namespace specification to a persistent volume or claim
+func setAccessMetadata(obj client.Object, fs *lusv1beta1.LustreFileSystem, namespace string, namespaceSpec lusv1beta1.LustreFileSystemNamespaceSpec, mode corev1.PersistentVolumeAccessMode) error {
+	accessLabels, err := fs.NamespaceLabels(namespace, namespaceSpec, mode)
+	if err != nil {
+		return err
+	}
+
+	accessAnnotations, err := fs.NamespaceAnnotations(namespace, namespaceSpec, mode)
+	if err != nil {
+		return err
+	}
+
+	if len(accessLabels) != 0 {
+		objLabels := obj.GetLabels()
+		if objLabels == nil {
+			objLabels = map[string]string{}
+		}
+
+		maps.Copy(objLabels, accessLabels)
+		obj.SetLabels(objLabels)
+	}
+
+	if len(accessAnnotations) != 0 {
+		objAnnotations := obj.GetAnnotations()
+		if objAnnotations == nil {
+			objAnnotations = map[string]string{}
+		}
+
+		maps.Copy(objAnnotations, accessAnnotations)
+		obj.SetAnnotations(objAnnotations)
+	}
+
+	return nil
+}
+
+// getOwnerHandler maps a persistent volume or claim to the LustreFileSystem named by its owner labels
+func (r *LustreFileSystemReconciler) getOwnerHandler(ctx context.Context, o client.Object) []reconcile.Request {
+	ownerLabels := o.GetLabels()
+
+	name, ok := ownerLabels[lusv1beta1.OwnerNameLabel]
+	if !ok {
+		return nil
+	}
+
+	namespace, ok := ownerLabels[lusv1beta1.OwnerNamespaceLabel]
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}}
+}
+
+// ManagerCacheOptions returns the cache options the manager must use for the LustreFileSystem controller.
+// Only the persistent volumes and claims created by the controller are cached.
+func ManagerCacheOptions() cache.Options {
+	owned, _ := labels.NewRequirement(lusv1beta1.OwnerNameLabel, selection.Exists, nil)
+	selector := labels.NewSelector().Add(*owned)
+
+	return cache.Options{
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.PersistentVolume{}:      {Label: selector},
+			&corev1.PersistentVolumeClaim{}: {Label: selector},
+		},
+	}
+}
+
+// ManagerClientOptions returns the client options the manager must use for the LustreFileSystem controller.
+// Persistent volumes and claims are always read from the API server so objects created before the owner
+// labels existed, which are missing from the label filtered cache, are found and adopted. Pods are only
+// read to find the users of a claim, so they're read from the API server rather than cached.
+func ManagerClientOptions() client.Options {
+	return client.Options{
+		Cache: &client.CacheOptions{
+			DisableFor: []client.Object{
+				&corev1.PersistentVolume{},
+				&corev1.PersistentVolumeClaim{},
+				&corev1.Pod{},
+			},
+		},
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *LustreFileSystemReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	if err := metrics.RegisterCollector(mgr.GetClient()); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &lusv1beta1.LustreFileSystem{}, namespacesIndexField, indexNamespaces); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &lusv1beta1.LustreFileSystem{}, namespaceSelectorIndexField, indexNamespaceSelector); err != nil {
+		return err
+	}
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &lusv1beta1.LustreFileSystem{}, storageClassIndexField, indexStorageClass); err != nil {
+		return err
+	}
+
+	// The index is shared with the LustreFileSystemAccess controller
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &lusv1beta1.LustreFileSystemAccess{}, fileSystemRefIndexField, indexFileSystemRef); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		For(&lusv1beta1.LustreFileSystem{}).
 		Watches(
 			// Watch all namespaces for changes to ensure lustrefilesystem resources stay current
 			&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.getLustreFileSystemsHandler),
+			builder.WithPredicates(namespaceChangedPredicate()),
+		).
+		Watches(
+			// Watch the generated persistent volumes and claims so they are recreated or reverted if changed
+			&corev1.PersistentVolume{}, handler.EnqueueRequestsFromMapFunc(r.getOwnerHandler),
+		).
+		Watches(
+			&corev1.PersistentVolumeClaim{}, handler.EnqueueRequestsFromMapFunc(r.getOwnerHandler),
+		).
+		Watches(
+			// Watch the storage classes so the condition follows a storage class that is created, changed, or deleted
+			&storagev1.StorageClass{}, handler.EnqueueRequestsFromMapFunc(r.getStorageClassHandler),
+		).
+		Watches(
+			// Watch the access requests so a namespace is granted or revoked access as its requests change
+			&lusv1beta1.LustreFileSystemAccess{}, handler.EnqueueRequestsFromMapFunc(r.getAccessRequestHandler),
 		).
 		Complete(r)
 }
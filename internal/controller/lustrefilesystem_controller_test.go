@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2023 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -20,17 +20,27 @@
 package controller
 
 import (
+	"path/filepath"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gstruct"
 
 	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	lusv1beta1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1beta1"
+	"github.com/NearNodeFlash/lustre-fs-operator/internal/lfs"
 )
 
 var _ = Describe("LustreFileSystem Controller", func() {
@@ -76,6 +86,54 @@ var _ = Describe("LustreFileSystem Controller", func() {
 		})
 	})
 
+	Context("with a storage class that does not exist", func() {
+		It("reports the storage class as not found", func() {
+			Eventually(func(g Gomega) *metav1.Condition {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+				return meta.FindStatusCondition(fs.Status.Conditions, lusv1beta1.ConditionStorageClassReady)
+			}).Should(And(
+				Not(BeNil()),
+				HaveField("Status", metav1.ConditionFalse),
+				HaveField("Reason", lusv1beta1.ConditionReasonStorageClassNotFound),
+			))
+		})
+	})
+
+	Context("with a managed storage class", func() {
+
+		BeforeEach(func() {
+			fs.Name = "controller-managed-storage-class"
+			fs.Spec.StorageClassName = "controller-managed-storage-class"
+			fs.Spec.StorageClassPolicy = lusv1beta1.StorageClassManaged
+		})
+
+		It("creates the storage class and deletes it with the file system", func() {
+			Eventually(func(g Gomega) *metav1.Condition {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+				return meta.FindStatusCondition(fs.Status.Conditions, lusv1beta1.ConditionStorageClassReady)
+			}).Should(And(
+				Not(BeNil()),
+				HaveField("Status", metav1.ConditionTrue),
+			))
+
+			sc := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: fs.Spec.StorageClassName}}
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(sc), sc)).Should(Succeed())
+			Expect(sc.Provisioner).To(Equal(fs.Spec.CSIDriver))
+			Expect(sc.Labels).To(HaveKeyWithValue(lusv1beta1.OwnerNameLabel, fs.Name))
+
+			By("deleting the file system")
+			Expect(k8sClient.Delete(ctx, fs)).Should(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(sc), sc))
+			}).Should(BeTrue())
+
+			Eventually(func() error {
+				return k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)
+			}).ShouldNot(Succeed())
+			fs = nil
+		})
+	})
+
 	Context("creates successfully with namespace but no mode", func() {
 		const namespace = "dummy-namespace"
 
@@ -95,6 +153,87 @@ var _ = Describe("LustreFileSystem Controller", func() {
 		})
 	})
 
+	Context("with a namespace that does not exist", func() {
+		const namespace = "missing-namespace"
+		const mode = corev1.ReadWriteMany
+
+		BeforeEach(func() {
+			fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+				namespace: {
+					Modes: []corev1.PersistentVolumeAccessMode{
+						mode,
+					},
+				},
+			}
+		})
+
+		It("reports the namespace as not found", func() {
+			Eventually(func(g Gomega) lusv1beta1.LustreFileSystemNamespaceAccessStatus {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+				g.Expect(fs.Status.Namespaces).To(HaveKey(namespace))
+				g.Expect(fs.Status.Namespaces[namespace].Modes).To(HaveKey(mode))
+
+				return fs.Status.Namespaces[namespace].Modes[mode]
+			}).Should(MatchFields(IgnoreExtras, Fields{
+				"State":              Equal(lusv1beta1.NamespaceAccessNamespaceNotFound),
+				"Message":            ContainSubstring(namespace),
+				"LastTransitionTime": Not(BeNil()),
+			}))
+
+			Expect(meta.IsStatusConditionTrue(fs.Status.Conditions, lusv1beta1.ConditionReady)).To(BeFalse())
+		})
+	})
+
+	Context("with a namespace selector", func() {
+		const namespace = "selected-namespace"
+		const mode = corev1.ReadWriteMany
+		const label = "lustre.access/home"
+
+		var ns *corev1.Namespace
+
+		BeforeEach(func() {
+			ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   namespace,
+				Labels: map[string]string{label: "rw"},
+			}}
+
+			// envtest never deletes a namespace, so it may be left over from an earlier run
+			Expect(client.IgnoreAlreadyExists(k8sClient.Create(ctx, ns))).Should(Succeed())
+
+			fs.Spec.NamespaceSelector = &lusv1beta1.LustreFileSystemNamespaceSelector{
+				LabelSelector: metav1.LabelSelector{
+					MatchLabels: map[string]string{label: "rw"},
+				},
+				Modes: []corev1.PersistentVolumeAccessMode{mode},
+			}
+		})
+
+		It("grants access to matching namespaces and revokes it when the label is removed", func() {
+			By("verifying the selected namespace is ready")
+			Eventually(func(g Gomega) lusv1beta1.LustreFileSystemNamespaceStatus {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+				g.Expect(fs.Status.Namespaces).To(HaveKey(namespace))
+
+				return fs.Status.Namespaces[namespace]
+			}).Should(MatchFields(IgnoreExtras, Fields{
+				"MatchedBySelector": BeTrue(),
+				"Modes":             HaveKeyWithValue(mode, MatchFields(IgnoreExtras, Fields{"State": Equal(lusv1beta1.NamespaceAccessReady)})),
+			}))
+
+			By("removing the label from the namespace")
+			Eventually(func(g Gomega) {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(ns), ns)).Should(Succeed())
+				delete(ns.Labels, label)
+				g.Expect(k8sClient.Update(ctx, ns)).Should(Succeed())
+			}).Should(Succeed())
+
+			Eventually(func(g Gomega) map[string]lusv1beta1.LustreFileSystemNamespaceStatus {
+				g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+				return fs.Status.Namespaces
+			}).ShouldNot(HaveKey(namespace))
+		})
+	})
+
 	Context("with a dummy namespace", Ordered, func() {
 		const namespace = "dummy-namespace"
 		const mode = corev1.ReadWriteMany
@@ -133,6 +272,9 @@ var _ = Describe("LustreFileSystem Controller", func() {
 				"State":                    Equal(lusv1beta1.NamespaceAccessReady),
 				"PersistentVolumeRef":      Not(BeNil()),
 				"PersistentVolumeClaimRef": Not(BeNil()),
+				"Message":                  BeEmpty(),
+				"Pods":                     BeEmpty(),
+				"LastTransitionTime":       Not(BeNil()),
 			}))
 
 			By("verifying PV exists")
@@ -143,6 +285,7 @@ var _ = Describe("LustreFileSystem Controller", func() {
 			}
 
 			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+			Expect(pv.Spec.CSI.Driver).To(Equal(fs.Spec.CSIDriver))
 
 			By("verifying PVC exists")
 			pvc := &corev1.PersistentVolumeClaim{
@@ -171,6 +314,89 @@ var _ = Describe("LustreFileSystem Controller", func() {
 				validateCreateOccurredFn()
 			})
 
+			It("reports the Ready condition for the current generation", func() {
+				validateCreateOccurredFn()
+
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.ObservedGeneration).To(Equal(fs.Generation))
+					g.Expect(meta.IsStatusConditionTrue(fs.Status.Conditions, lusv1beta1.ConditionReady)).To(BeTrue())
+					g.Expect(meta.IsStatusConditionTrue(fs.Status.Conditions, lusv1beta1.ConditionNamespacesReconciled)).To(BeTrue())
+					g.Expect(meta.IsStatusConditionFalse(fs.Status.Conditions, lusv1beta1.ConditionDegraded)).To(BeTrue())
+					g.Expect(meta.IsStatusConditionFalse(fs.Status.Conditions, lusv1beta1.ConditionDeleting)).To(BeTrue())
+				}).Should(Succeed())
+			})
+
+			It("records access granted events", func() {
+				validateCreateOccurredFn()
+
+				By("verifying the event on the file system")
+				Eventually(func(g Gomega) []corev1.Event {
+					events := &corev1.EventList{}
+					g.Expect(k8sClient.List(ctx, events, client.InNamespace(fs.Namespace))).Should(Succeed())
+					return events.Items
+				}).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"InvolvedObject": MatchFields(IgnoreExtras, Fields{"Kind": Equal("LustreFileSystem"), "Name": Equal(fs.Name)}),
+					"Reason":         Equal("AccessGranted"),
+					"Type":           Equal(corev1.EventTypeNormal),
+				})))
+
+				By("verifying the event on the persistent volume claim")
+				Eventually(func(g Gomega) []corev1.Event {
+					events := &corev1.EventList{}
+					g.Expect(k8sClient.List(ctx, events, client.InNamespace(namespace))).Should(Succeed())
+					return events.Items
+				}).Should(ContainElement(MatchFields(IgnoreExtras, Fields{
+					"InvolvedObject": MatchFields(IgnoreExtras, Fields{"Kind": Equal("PersistentVolumeClaim"), "Name": Equal(fs.PersistentVolumeClaimName(namespace, mode))}),
+					"Reason":         Equal("AccessGranted"),
+				})))
+			})
+
+			It("reports the namespace access state metric", func() {
+				validateCreateOccurredFn()
+
+				Eventually(func(g Gomega) float64 {
+					return namespaceAccessStateMetric(g, fs, namespace, mode, lusv1beta1.NamespaceAccessReady)
+				}).Should(Equal(1.0))
+			})
+
+			It("labels the pv/pvc with the owning file system", func() {
+				validateCreateOccurredFn()
+
+				ownerLabels := map[string]string{
+					lusv1beta1.OwnerNameLabel:      fs.Name,
+					lusv1beta1.OwnerNamespaceLabel: fs.Namespace,
+				}
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				for key, value := range ownerLabels {
+					Expect(pv.GetLabels()).To(HaveKeyWithValue(key, value))
+				}
+
+				pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeClaimName(namespace, mode), Namespace: namespace}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).Should(Succeed())
+				for key, value := range ownerLabels {
+					Expect(pvc.GetLabels()).To(HaveKeyWithValue(key, value))
+				}
+			})
+
+			It("reverts a hand-edited pv claim reference", func() {
+				validateCreateOccurredFn()
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+					pv.Spec.ClaimRef.Name = "some-other-claim"
+					g.Expect(k8sClient.Update(ctx, pv)).Should(Succeed())
+				}).Should(Succeed())
+
+				Eventually(func(g Gomega) string {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+					return pv.Spec.ClaimRef.Name
+				}).Should(Equal(fs.PersistentVolumeClaimName(namespace, mode)))
+			})
+
 			It("does not delete until only our finalizer is left", func() {
 				const finalizer = "test-finalizer"
 
@@ -210,6 +436,888 @@ var _ = Describe("LustreFileSystem Controller", func() {
 			})
 		})
 
+		Context("with mount options and volume attributes", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-mount-options"
+				fs.Spec.MountOptions = []string{"flock", "noatime"}
+				fs.Spec.VolumeAttributes = map[string]string{"networks": "tcp0", "other": "value"}
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes:            []corev1.PersistentVolumeAccessMode{mode},
+						MountOptions:     []string{"flock", "user_xattr"},
+						VolumeAttributes: map[string]string{"networks": "o2ib1"},
+					},
+				}
+			})
+
+			It("applies the namespace overrides to the pv", func() {
+				validateCreateOccurredFn()
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.MountOptions).To(Equal([]string{"flock", "user_xattr"}))
+				Expect(pv.Spec.CSI.VolumeAttributes).To(Equal(map[string]string{"networks": "o2ib1", "other": "value"}))
+			})
+		})
+
+		Context("with MGS nodes", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-mgs-nodes"
+				fs.Spec.MgsNids = ""
+				fs.Spec.MgsNodes = []lusv1beta1.LustreFileSystemMgsNode{
+					{Nids: []string{"172.0.0.1@tcp", "10.1.0.1@o2ib1"}},
+					{Nids: []string{"172.0.0.2@tcp"}},
+				}
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+			})
+
+			It("builds the volume handle from the MGS nodes", func() {
+				validateCreateOccurredFn()
+
+				Expect(fs.Spec.MgsNids).To(Equal("172.0.0.1@tcp,10.1.0.1@o2ib1:172.0.0.2@tcp"))
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.CSI.VolumeHandle).To(Equal("172.0.0.1@tcp,10.1.0.1@o2ib1:172.0.0.2@tcp:/" + fs.Spec.Name))
+			})
+		})
+
+		Context("with a read only mode", func() {
+			const readOnlyMode = corev1.ReadOnlyMany
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-read-only"
+				fs.Spec.MountOptions = []string{"rw", "flock"}
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode, readOnlyMode},
+					},
+				}
+			})
+
+			It("creates persistent volumes with the access mode of the grant", func() {
+				validateCreateOccurredFn()
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.AccessModes).To(ConsistOf(mode))
+				Expect(pv.Spec.CSI.ReadOnly).To(BeFalse())
+				Expect(pv.Spec.MountOptions).To(Equal([]string{"rw", "flock"}))
+
+				By("verifying the read only PV is mounted read only")
+				Eventually(func(g Gomega) *corev1.PersistentVolume {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.Namespaces[namespace].Modes).To(HaveKey(readOnlyMode))
+
+					pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, readOnlyMode)}}
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+					return pv
+				}).Should(WithTransform(func(pv *corev1.PersistentVolume) bool {
+					return pv.Spec.CSI.ReadOnly
+				}, BeTrue()))
+
+				pv = &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, readOnlyMode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.AccessModes).To(ConsistOf(readOnlyMode))
+				Expect(pv.Spec.MountOptions).To(Equal([]string{"flock", "ro"}))
+			})
+		})
+
+		Context("with a claim name, labels and annotations", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-claim-name"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes:       []corev1.PersistentVolumeAccessMode{mode},
+						ClaimName:   "home",
+						Labels:      map[string]string{"example.com/filesystem": "{{.FileSystemName}}"},
+						Annotations: map[string]string{"example.com/mode": "{{.Mode}}"},
+					},
+				}
+			})
+
+			It("creates the claim with the chosen name and metadata", func() {
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemNamespaceAccessStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.Namespaces).To(HaveKey(namespace))
+					return fs.Status.Namespaces[namespace].Modes[mode]
+				}).Should(MatchFields(IgnoreExtras, Fields{
+					"State":                    Equal(lusv1beta1.NamespaceAccessReady),
+					"PersistentVolumeClaimRef": PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal("home")})),
+				}))
+
+				pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "home", Namespace: namespace}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).Should(Succeed())
+				Expect(pvc.Labels).To(HaveKeyWithValue("example.com/filesystem", fs.Spec.Name))
+				Expect(pvc.Labels).To(HaveKeyWithValue(lusv1beta1.OwnerNameLabel, fs.Name))
+				Expect(pvc.Annotations).To(HaveKeyWithValue("example.com/mode", "readwritemany"))
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.ClaimRef.Name).To(Equal("home"))
+				Expect(pv.Labels).To(HaveKeyWithValue("example.com/filesystem", fs.Spec.Name))
+				Expect(pv.Annotations).To(HaveKeyWithValue("example.com/mode", "readwritemany"))
+			})
+		})
+
+		Context("with a claim name used by another claim", func() {
+			var pvc *corev1.PersistentVolumeClaim
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-claim-conflict"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes:     []corev1.PersistentVolumeAccessMode{mode},
+						ClaimName: "scratch",
+					},
+				}
+
+				pvc = &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: "scratch", Namespace: namespace},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						AccessModes: []corev1.PersistentVolumeAccessMode{mode},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: persistentVolumeResourceQuantity},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pvc)).Should(Succeed())
+			})
+
+			AfterEach(func() {
+				Expect(k8sClient.Delete(ctx, pvc)).Should(Succeed())
+			})
+
+			It("reports the conflict and leaves the claim alone", func() {
+				Eventually(func(g Gomega) lusv1beta1.NamespaceAccessState {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.Namespaces).To(HaveKey(namespace))
+					return fs.Status.Namespaces[namespace].Modes[mode].State
+				}).Should(Equal(lusv1beta1.NamespaceAccessPVCConflict))
+
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).Should(Succeed())
+				Expect(pvc.Labels).ToNot(HaveKey(lusv1beta1.OwnerNameLabel))
+
+				By("deleting the file system")
+				Expect(k8sClient.Delete(ctx, fs)).Should(Succeed())
+				Eventually(func() bool {
+					return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs))
+				}).Should(BeTrue())
+				fs = nil
+
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)).Should(Succeed())
+				Expect(pvc.DeletionTimestamp.IsZero()).To(BeTrue())
+			})
+		})
+
+		Context("with an available persistent volume of another file system with the same name", func() {
+			var pv *corev1.PersistentVolume
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-pv-owner"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+
+				By("creating the PV of the other file system")
+				volumeMode := corev1.PersistentVolumeFilesystem
+				pv = &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: fs.PersistentVolumeName(namespace, mode),
+						Labels: map[string]string{
+							lusv1beta1.OwnerNameLabel:      "other",
+							lusv1beta1.OwnerNamespaceLabel: corev1.NamespaceDefault,
+						},
+					},
+					Spec: corev1.PersistentVolumeSpec{
+						VolumeMode:       &volumeMode,
+						StorageClassName: fs.Spec.StorageClassName,
+						AccessModes:      []corev1.PersistentVolumeAccessMode{mode},
+						Capacity:         corev1.ResourceList{corev1.ResourceStorage: persistentVolumeResourceQuantity},
+						PersistentVolumeSource: corev1.PersistentVolumeSource{
+							CSI: &corev1.CSIPersistentVolumeSource{
+								Driver:       "lustre-csi.hpe.com",
+								FSType:       "lustre",
+								VolumeHandle: "10.0.0.1@tcp:/other",
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pv)).Should(Succeed())
+			})
+
+			It("reports the conflict and leaves the persistent volume alone", func() {
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemNamespaceAccessStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces[namespace].Modes[mode]
+				}).Should(MatchFields(IgnoreExtras, Fields{
+					"State":   Equal(lusv1beta1.NamespaceAccessPVConflict),
+					"Message": ContainSubstring("belongs to LustreFileSystem 'default/other'"),
+				}))
+
+				By("deleting the file system")
+				Expect(k8sClient.Delete(ctx, fs)).Should(Succeed())
+				Eventually(func() bool {
+					return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs))
+				}).Should(BeTrue())
+				fs = nil
+
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.DeletionTimestamp.IsZero()).To(BeTrue())
+				Expect(pv.Labels).To(HaveKeyWithValue(lusv1beta1.OwnerNameLabel, "other"))
+				Expect(pv.Spec.ClaimRef).To(BeNil())
+			})
+		})
+
+		Context("with a claim bound to a persistent volume named under the earlier naming scheme", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-legacy"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+
+				By("creating the PV and PVC under the earlier naming scheme")
+				// The PV is built as the operator built it before the naming scheme changed
+				volumeMode := corev1.PersistentVolumeFilesystem
+				pv := &corev1.PersistentVolume{
+					ObjectMeta: metav1.ObjectMeta{Name: fs.LegacyPersistentVolumeName(namespace, mode)},
+					Spec: corev1.PersistentVolumeSpec{
+						VolumeMode:       &volumeMode,
+						StorageClassName: fs.Spec.StorageClassName,
+						AccessModes:      []corev1.PersistentVolumeAccessMode{mode},
+						Capacity:         corev1.ResourceList{corev1.ResourceStorage: persistentVolumeResourceQuantity},
+						ClaimRef: &corev1.ObjectReference{
+							Name:      fs.PersistentVolumeClaimName(namespace, mode),
+							Namespace: namespace,
+						},
+						PersistentVolumeSource: corev1.PersistentVolumeSource{
+							CSI: &corev1.CSIPersistentVolumeSource{
+								Driver:       "lustre-csi.hpe.com",
+								FSType:       "lustre",
+								VolumeHandle: fs.Spec.MgsNids + ":/" + fs.Spec.Name,
+							},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pv)).Should(Succeed())
+
+				storageClassName := fs.Spec.StorageClassName
+				pvc := &corev1.PersistentVolumeClaim{
+					ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeClaimName(namespace, mode), Namespace: namespace},
+					Spec: corev1.PersistentVolumeClaimSpec{
+						StorageClassName: &storageClassName,
+						VolumeName:       pv.Name,
+						AccessModes:      []corev1.PersistentVolumeAccessMode{mode},
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{corev1.ResourceStorage: persistentVolumeResourceQuantity},
+						},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pvc)).Should(Succeed())
+			})
+
+			It("keeps the claim on its persistent volume", func() {
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemNamespaceAccessStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.Namespaces).To(HaveKey(namespace))
+					return fs.Status.Namespaces[namespace].Modes[mode]
+				}).Should(MatchFields(IgnoreExtras, Fields{
+					"State":               Equal(lusv1beta1.NamespaceAccessReady),
+					"PersistentVolumeRef": PointTo(MatchFields(IgnoreExtras, Fields{"Name": Equal(fs.LegacyPersistentVolumeName(namespace, mode))})),
+				}))
+
+				By("verifying the PV is adopted and no PV is created under the new name")
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.LegacyPersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Labels).To(HaveKeyWithValue(lusv1beta1.OwnerNameLabel, fs.Name))
+
+				Expect(fs.PersistentVolumeName(namespace, mode)).ToNot(Equal(pv.Name))
+				err := k8sClient.Get(ctx, client.ObjectKey{Name: fs.PersistentVolumeName(namespace, mode)}, &corev1.PersistentVolume{})
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			})
+		})
+
+		Context("with a rollover of the MGS NIDs", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-rollover"
+				fs.Spec.MgsNidsUpdatePolicy = lusv1beta1.MgsNidsUpdateRollover
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+			})
+
+			It("waits for the pods using the claim before moving it to the new persistent volume", func() {
+				validateCreateOccurredFn()
+
+				By("creating a pod that uses the claim")
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "rollover", Namespace: namespace},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+						Volumes: []corev1.Volume{{
+							Name: "lustre",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: fs.PersistentVolumeClaimName(namespace, mode)},
+							},
+						}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+
+				By("changing the MGS NIDs")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					fs.Spec.MgsNids = "172.0.0.2@tcp"
+					fs.Spec.MgsNodes = nil
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+				}).Should(Succeed())
+
+				exportPath := "172.0.0.2@tcp:/" + fs.Spec.Name
+				Eventually(func(g Gomega) *lusv1beta1.LustreFileSystemNamespaceRolloverStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.Namespaces[namespace].Modes[mode].State).To(Equal(lusv1beta1.NamespaceAccessRolloverPending))
+					return fs.Status.Namespaces[namespace].Rollover
+				}).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+					"ExportPath":   Equal(exportPath),
+					"PendingModes": ConsistOf(mode),
+					"Pods":         ConsistOf(namespace + "/" + pod.Name),
+				})))
+
+				By("verifying the new PV exists alongside the previous PV")
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeRolloverName(namespace, mode, exportPath)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.CSI.VolumeHandle).To(Equal(exportPath))
+
+				previousPV := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(previousPV), previousPV)).Should(Succeed())
+				Expect(previousPV.Spec.CSI.VolumeHandle).To(Equal("172.0.0.1@tcp:/" + fs.Spec.Name))
+
+				By("deleting the pod")
+				// envtest has no kubelet to finish a graceful deletion
+				Expect(k8sClient.Delete(ctx, pod, client.GracePeriodSeconds(0))).Should(Succeed())
+
+				// Pods aren't watched, so the file system is touched to reconcile it rather than waiting for its requeue
+				pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeClaimName(namespace, mode), Namespace: namespace}}
+				Eventually(func(g Gomega) bool {
+					err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)
+					if errors.IsNotFound(err) || !pvc.DeletionTimestamp.IsZero() || pvc.Spec.VolumeName == pv.Name {
+						return true
+					}
+
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					fs.SetAnnotations(map[string]string{"test/reconcile": time.Now().String()})
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+					return false
+				}).WithPolling(time.Second).WithTimeout(10 * time.Second).Should(BeTrue())
+			})
+		})
+
+		Context("with a pod using the claim", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-in-use"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+			})
+
+			It("holds the deletion until it is forced", func() {
+				validateCreateOccurredFn()
+
+				By("creating a pod that uses the claim")
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "in-use", Namespace: namespace},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+						Volumes: []corev1.Volume{{
+							Name: "lustre",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: fs.PersistentVolumeClaimName(namespace, mode)},
+							},
+						}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).Should(Succeed())
+				})
+
+				By("deleting the file system")
+				Expect(k8sClient.Delete(ctx, fs)).Should(Succeed())
+
+				Eventually(func(g Gomega) []string {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(meta.FindStatusCondition(fs.Status.Conditions, lusv1beta1.ConditionDeleting)).To(PointTo(MatchFields(IgnoreExtras, Fields{
+						"Status": Equal(metav1.ConditionTrue),
+						"Reason": Equal(lusv1beta1.ConditionReasonInUse),
+					})))
+					return fs.Status.DeletionBlockedBy
+				}).Should(ConsistOf(namespace + "/" + pod.Name))
+
+				By("forcing the deletion")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					fs.SetAnnotations(map[string]string{lusv1beta1.ForceDeleteAnnotation: "true"})
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+				}).Should(Succeed())
+
+				Eventually(func() error {
+					return k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)
+				}).ShouldNot(Succeed())
+
+				fs = nil // we already cleaned up
+			})
+		})
+
+		Context("revoking an access with a pod using the claim", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-revoking"
+				gracePeriod := int64(3600)
+				fs.Spec.RevocationGracePeriodSeconds = &gracePeriod
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+			})
+
+			It("waits for the pod until the grace period is removed", func() {
+				validateCreateOccurredFn()
+
+				By("creating a pod that uses the claim")
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "revoking", Namespace: namespace},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+						Volumes: []corev1.Volume{{
+							Name: "lustre",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: fs.PersistentVolumeClaimName(namespace, mode)},
+							},
+						}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).Should(Succeed())
+				})
+
+				By("removing the namespace")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					fs.Spec.Namespaces = nil
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+				}).Should(Succeed())
+
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemNamespaceAccessStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces[namespace].Modes[mode]
+				}).Should(MatchFields(IgnoreExtras, Fields{
+					"State": Equal(lusv1beta1.NamespaceAccessRevoking),
+					"Pods":  ConsistOf(namespace + "/" + pod.Name),
+				}))
+
+				Expect(meta.IsStatusConditionTrue(fs.Status.Conditions, lusv1beta1.ConditionReady)).To(BeTrue())
+
+				Eventually(func(g Gomega) float64 {
+					return namespaceAccessStateMetric(g, fs, namespace, mode, lusv1beta1.NamespaceAccessRevoking)
+				}).Should(Equal(1.0))
+
+				By("removing the grace period")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					gracePeriod := int64(0)
+					fs.Spec.RevocationGracePeriodSeconds = &gracePeriod
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+				}).Should(Succeed())
+
+				Eventually(func(g Gomega) map[string]lusv1beta1.LustreFileSystemNamespaceStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces
+				}).ShouldNot(HaveKey(namespace))
+			})
+		})
+
+		Context("deleting the file system while an access is revoking", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-revoking-delete"
+				gracePeriod := int64(3600)
+				fs.Spec.RevocationGracePeriodSeconds = &gracePeriod
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+			})
+
+			It("waits for the pods of the revoking access and removes its claim", func() {
+				validateCreateOccurredFn()
+
+				By("creating a pod that uses the claim")
+				pod := &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "revoking-delete", Namespace: namespace},
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{Name: "app", Image: "busybox"}},
+						Volumes: []corev1.Volume{{
+							Name: "lustre",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: fs.PersistentVolumeClaimName(namespace, mode)},
+							},
+						}},
+					},
+				}
+				Expect(k8sClient.Create(ctx, pod)).Should(Succeed())
+				DeferCleanup(func() {
+					Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pod))).Should(Succeed())
+				})
+
+				By("removing the namespace")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					fs.Spec.Namespaces = nil
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+				}).Should(Succeed())
+
+				Eventually(func(g Gomega) lusv1beta1.NamespaceAccessState {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces[namespace].Modes[mode].State
+				}).Should(Equal(lusv1beta1.NamespaceAccessRevoking))
+
+				By("deleting the file system")
+				Expect(k8sClient.Delete(ctx, fs)).Should(Succeed())
+
+				Eventually(func(g Gomega) []string {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.DeletionBlockedBy
+				}).Should(ConsistOf(namespace + "/" + pod.Name))
+
+				By("forcing the deletion")
+				Eventually(func(g Gomega) {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					fs.SetAnnotations(map[string]string{lusv1beta1.ForceDeleteAnnotation: "true"})
+					g.Expect(k8sClient.Update(ctx, fs)).Should(Succeed())
+				}).Should(Succeed())
+
+				Eventually(func() error {
+					return k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)
+				}).ShouldNot(Succeed())
+
+				pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeClaimName(namespace, mode), Namespace: namespace}}
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, client.ObjectKeyFromObject(pvc), pvc)
+					return errors.IsNotFound(err) || !pvc.DeletionTimestamp.IsZero()
+				}).Should(BeTrue())
+
+				fs = nil // we already cleaned up
+			})
+		})
+
+		Context("with a subdirectory", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-subdirectory"
+				fs.Spec.Subdirectory = "projects/{{.Namespace}}"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+					},
+				}
+			})
+
+			It("exports the namespace subdirectory", func() {
+				validateCreateOccurredFn()
+
+				exportPath := fs.Spec.MgsNids + ":/" + fs.Spec.Name + "/projects/" + namespace
+				Expect(fs.Status.Namespaces[namespace].ExportPath).To(Equal(exportPath))
+
+				pv := &corev1.PersistentVolume{ObjectMeta: metav1.ObjectMeta{Name: fs.PersistentVolumeName(namespace, mode)}}
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(pv), pv)).Should(Succeed())
+				Expect(pv.Spec.CSI.VolumeHandle).To(Equal(exportPath))
+			})
+		})
+
+		Context("with an access that expires", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-expiry"
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes: []corev1.PersistentVolumeAccessMode{mode},
+						TTL:   &metav1.Duration{Duration: 2 * time.Second},
+					},
+				}
+			})
+
+			It("revokes the access at its expiry and records the expiration", func() {
+				Eventually(func(g Gomega) lusv1beta1.NamespaceAccessState {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					g.Expect(fs.Status.Namespaces).To(HaveKey(namespace))
+					return fs.Status.Namespaces[namespace].Modes[mode].State
+				}).WithTimeout(10 * time.Second).Should(Equal(lusv1beta1.NamespaceAccessExpired))
+
+				namespaceStatus := fs.Status.Namespaces[namespace]
+				Expect(namespaceStatus.GrantedAt).NotTo(BeNil())
+				Expect(namespaceStatus.ExpiresAt).NotTo(BeNil())
+				Expect(namespaceStatus.ExpiresAt.Sub(namespaceStatus.GrantedAt.Time)).To(Equal(2 * time.Second))
+
+				By("removing the expiry")
+				Eventually(func(g Gomega) error {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					namespaceSpec := fs.Spec.Namespaces[namespace]
+					namespaceSpec.TTL = nil
+					fs.Spec.Namespaces[namespace] = namespaceSpec
+					return k8sClient.Update(ctx, fs)
+				}).Should(Succeed())
+
+				validateCreateOccurredFn()
+				Expect(fs.Status.Namespaces[namespace].ExpiresAt).To(BeNil())
+			})
+		})
+
+		Context("with a quota", func() {
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-quota"
+				inodeLimit := int64(1000)
+				fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					namespace: {
+						Modes:        []corev1.PersistentVolumeAccessMode{mode},
+						Subdirectory: "projects/{{.Namespace}}",
+						Quota: &lusv1beta1.LustreFileSystemNamespaceQuota{
+							BlockLimit: resource.NewQuantity(1<<30, resource.BinarySI),
+							InodeLimit: &inodeLimit,
+						},
+					},
+				}
+			})
+
+			It("sets the project quota of the namespace subdirectory and removes it with the quota", func() {
+				validateCreateOccurredFn()
+
+				var quota *lusv1beta1.LustreFileSystemNamespaceQuotaStatus
+				Eventually(func(g Gomega) *lusv1beta1.LustreFileSystemNamespaceQuotaStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					quota = fs.Status.Namespaces[namespace].Quota
+					return quota
+				}).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+					"ProjectID":      BeNumerically(">=", firstProjectID),
+					"Path":           Equal(filepath.Join(fs.Spec.MountRoot, "projects", namespace)),
+					"Limits":         Not(BeNil()),
+					"LastUpdateTime": Not(BeNil()),
+					"Message":        BeEmpty(),
+				})))
+
+				projectID, found := fakeLfs.Project(quota.Path)
+				Expect(found).To(BeTrue())
+				Expect(projectID).To(Equal(quota.ProjectID))
+
+				limits, found := fakeLfs.Limits(fs.Spec.MountRoot, quota.ProjectID)
+				Expect(found).To(BeTrue())
+				Expect(limits).To(Equal(lfs.Limits{BlockBytes: 1 << 30, Inodes: 1000}))
+
+				By("removing the quota")
+				Eventually(func(g Gomega) error {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					namespaceSpec := fs.Spec.Namespaces[namespace]
+					namespaceSpec.Quota = nil
+					fs.Spec.Namespaces[namespace] = namespaceSpec
+					return k8sClient.Update(ctx, fs)
+				}).Should(Succeed())
+
+				Eventually(func(g Gomega) *lusv1beta1.LustreFileSystemNamespaceQuotaStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces[namespace].Quota
+				}).Should(BeNil())
+
+				_, found = fakeLfs.Project(quota.Path)
+				Expect(found).To(BeFalse())
+
+				limits, _ = fakeLfs.Limits(fs.Spec.MountRoot, quota.ProjectID)
+				Expect(limits).To(Equal(lfs.Limits{}))
+			})
+
+			Context("with a project ID already set on the subdirectory", func() {
+				const projectID = int64(4242)
+
+				BeforeEach(func() {
+					fs.Name = "controller-quota-existing-project"
+					path := filepath.Join(fs.Spec.MountRoot, "projects", namespace)
+					Expect(fakeLfs.SetProject(ctx, path, projectID)).To(Succeed())
+					DeferCleanup(func() {
+						Expect(fakeLfs.ClearProject(ctx, path)).To(Succeed())
+					})
+				})
+
+				It("keeps the project ID of the subdirectory", func() {
+					validateCreateOccurredFn()
+
+					Eventually(func(g Gomega) *lusv1beta1.LustreFileSystemNamespaceQuotaStatus {
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+						return fs.Status.Namespaces[namespace].Quota
+					}).Should(PointTo(MatchFields(IgnoreExtras, Fields{
+						"ProjectID": Equal(projectID),
+						"Limits":    Not(BeNil()),
+					})))
+
+					limits, found := fakeLfs.Limits(fs.Spec.MountRoot, projectID)
+					Expect(found).To(BeTrue())
+					Expect(limits).To(Equal(lfs.Limits{BlockBytes: 1 << 30, Inodes: 1000}))
+				})
+			})
+		})
+
+		Context("with an access policy", func() {
+			var access *lusv1beta1.LustreFileSystemAccess
+
+			BeforeEach(func() {
+				// envtest can't delete PVs, so use a name that gives this file system its own PV
+				fs.Name = "controller-access-request"
+				fs.Spec.AccessPolicy = &lusv1beta1.LustreFileSystemAccessPolicy{
+					Namespaces: []string{namespace},
+					Modes:      []corev1.PersistentVolumeAccessMode{mode},
+					Approval:   lusv1beta1.AccessApprovalAutomatic,
+				}
+
+				access = &lusv1beta1.LustreFileSystemAccess{
+					ObjectMeta: metav1.ObjectMeta{Name: "access-request", Namespace: namespace},
+					Spec: lusv1beta1.LustreFileSystemAccessSpec{
+						FileSystemRef: lusv1beta1.LustreFileSystemReference{Name: fs.Name, Namespace: fs.Namespace},
+						Mode:          mode,
+					},
+				}
+			})
+
+			AfterEach(func() {
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, access))).Should(Succeed())
+			})
+
+			It("grants an approved request and revokes it when the request is deleted", func() {
+				Expect(k8sClient.Create(ctx, access)).Should(Succeed())
+
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemAccessStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(access), access)).Should(Succeed())
+					return access.Status
+				}).Should(MatchFields(IgnoreExtras, Fields{
+					"State":                    Equal(lusv1beta1.AccessRequestReady),
+					"PersistentVolumeClaimRef": Equal(&corev1.LocalObjectReference{Name: fs.PersistentVolumeClaimName(namespace, mode)}),
+				}))
+
+				approval := meta.FindStatusCondition(access.Status.Conditions, lusv1beta1.ConditionApproved)
+				Expect(approval).NotTo(BeNil())
+				Expect(approval.Reason).To(Equal(lusv1beta1.ConditionReasonAutoApproved))
+
+				validateCreateOccurredFn()
+
+				By("deleting the request")
+				Expect(k8sClient.Delete(ctx, access)).Should(Succeed())
+				Eventually(func(g Gomega) map[string]lusv1beta1.LustreFileSystemNamespaceStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces
+				}).ShouldNot(HaveKey(namespace))
+			})
+
+			It("reports a request for a file system that does not exist", func() {
+				access.Spec.FileSystemRef.Name = "controller-access-request-missing"
+				Expect(k8sClient.Create(ctx, access)).Should(Succeed())
+
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemAccessState {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(access), access)).Should(Succeed())
+					return access.Status.State
+				}).Should(Equal(lusv1beta1.AccessRequestFileSystemNotFound))
+
+				approval := meta.FindStatusCondition(access.Status.Conditions, lusv1beta1.ConditionApproved)
+				Expect(approval).NotTo(BeNil())
+				Expect(approval.Status).To(Equal(metav1.ConditionFalse))
+				Expect(approval.Reason).To(Equal(lusv1beta1.ConditionReasonFileSystemNotFound))
+			})
+
+			It("denies a request for a mode the policy doesn't allow", func() {
+				access.Spec.Mode = corev1.ReadOnlyMany
+				Expect(k8sClient.Create(ctx, access)).Should(Succeed())
+
+				Eventually(func(g Gomega) lusv1beta1.LustreFileSystemAccessState {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(access), access)).Should(Succeed())
+					return access.Status.State
+				}).Should(Equal(lusv1beta1.AccessRequestDenied))
+
+				approval := meta.FindStatusCondition(access.Status.Conditions, lusv1beta1.ConditionApproved)
+				Expect(approval).NotTo(BeNil())
+				Expect(approval.Reason).To(Equal(lusv1beta1.ConditionReasonNotAllowed))
+
+				Consistently(func(g Gomega) map[string]lusv1beta1.LustreFileSystemNamespaceStatus {
+					g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+					return fs.Status.Namespaces
+				}).ShouldNot(HaveKey(namespace))
+			})
+
+			Context("with a grant that names its claim", func() {
+
+				BeforeEach(func() {
+					fs.Name = "controller-access-request-conflict"
+					fs.Spec.AccessPolicy.Modes = append(fs.Spec.AccessPolicy.Modes, corev1.ReadOnlyMany)
+					fs.Spec.Namespaces = map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+						namespace: {
+							Modes:     []corev1.PersistentVolumeAccessMode{mode},
+							ClaimName: "controller-access-request-conflict",
+						},
+					}
+
+					access.Spec.FileSystemRef.Name = fs.Name
+					access.Spec.Mode = corev1.ReadOnlyMany
+				})
+
+				It("reports the conflict for a request for another mode", func() {
+					Expect(k8sClient.Create(ctx, access)).Should(Succeed())
+
+					Eventually(func(g Gomega) lusv1beta1.LustreFileSystemAccessStatus {
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(access), access)).Should(Succeed())
+						return access.Status
+					}).Should(MatchFields(IgnoreExtras, Fields{
+						"State":   Equal(lusv1beta1.AccessRequestConflict),
+						"Message": ContainSubstring("controller-access-request-conflict"),
+					}))
+
+					Consistently(func(g Gomega) map[corev1.PersistentVolumeAccessMode]lusv1beta1.LustreFileSystemNamespaceAccessStatus {
+						g.Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(fs), fs)).Should(Succeed())
+						return fs.Status.Namespaces[namespace].Modes
+					}).ShouldNot(HaveKey(corev1.ReadOnlyMany))
+				})
+			})
+		})
+
 		Context("adding a namespace post create", func() {
 			const mode = corev1.ReadWriteMany
 
@@ -278,3 +1386,68 @@ var _ = Describe("LustreFileSystem Controller", func() {
 		})
 	})
 })
+
+// namespaceAccessStateMetric returns the value of the namespace access state metric of the access for the state
+func namespaceAccessStateMetric(g Gomega, fs *lusv1beta1.LustreFileSystem, namespace string, mode corev1.PersistentVolumeAccessMode, state lusv1beta1.NamespaceAccessState) float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	for _, family := range families {
+		if family.GetName() != "lustre_fs_namespace_access_state" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+
+			if labels["filesystem"] == fs.Name && labels["namespace"] == namespace && labels["mode"] == string(mode) && labels["state"] == string(state) {
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+
+	return 0
+}
+
+var _ = Describe("LustreFileSystem Namespace Watch", func() {
+
+	It("indexes the namespaces referenced by the file system", func() {
+		fs := &lusv1beta1.LustreFileSystem{
+			Spec: lusv1beta1.LustreFileSystemSpec{
+				Namespaces: map[string]lusv1beta1.LustreFileSystemNamespaceSpec{
+					"ns1": {Modes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany}},
+					"ns2": {},
+				},
+			},
+		}
+
+		Expect(indexNamespaces(fs)).To(ConsistOf("ns1", "ns2"))
+	})
+
+	It("ignores namespace updates that don't change the phase or labels", func() {
+		p := namespaceChangedPredicate()
+
+		oldNamespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: "ns", Labels: map[string]string{"a": "b"}},
+			Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceActive},
+		}
+
+		By("changing only the annotations")
+		newNamespace := oldNamespace.DeepCopy()
+		newNamespace.Annotations = map[string]string{"c": "d"}
+		Expect(p.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: newNamespace})).To(BeFalse())
+
+		By("changing the labels")
+		newNamespace = oldNamespace.DeepCopy()
+		newNamespace.Labels["a"] = "c"
+		Expect(p.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: newNamespace})).To(BeTrue())
+
+		By("changing the phase")
+		newNamespace = oldNamespace.DeepCopy()
+		newNamespace.Status.Phase = corev1.NamespaceTerminating
+		Expect(p.Update(event.UpdateEvent{ObjectOld: oldNamespace, ObjectNew: newNamespace})).To(BeTrue())
+	})
+})
@@ -0,0 +1,263 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
+	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataWorkflowServices/dws/utils/updater"
+	lusv1beta1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1beta1"
+)
+
+const (
+	eventReasonAccessApproved   = "AccessApproved"
+	eventReasonAccessNotAllowed = "AccessNotAllowed"
+)
+
+// LustreFileSystemAccessReconciler reconciles a LustreFileSystemAccess object. It approves or denies the request
+// according to the access policy of the file system and reports the namespace access granted by the
+// LustreFileSystem controller, which creates the persistent volume and claim of an approved request.
+type LustreFileSystemAccessReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// IgnoredNamespaces filters the namespaces matching a namespace selector. It must match the filter of the
+	// LustreFileSystem controller. It may be nil.
+	IgnoredNamespaces *NamespaceFilter
+}
+
+//+kubebuilder:rbac:groups=lus.cray.hpe.com,resources=lustrefilesystemaccesses,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=lus.cray.hpe.com,resources=lustrefilesystemaccesses/status,verbs=get;update;patch
+
+// Reconcile evaluates the access request against the access policy of the file system and updates its status
+func (r *LustreFileSystemAccessReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	request := &lusv1beta1.LustreFileSystemAccess{}
+	if err := r.Get(ctx, req.NamespacedName, request); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// The LustreFileSystem controller revokes the access once the request is gone
+	if !request.GetDeletionTimestamp().IsZero() {
+		return ctrl.Result{}, nil
+	}
+
+	statusUpdater := updater.NewStatusUpdater[*lusv1beta1.LustreFileSystemAccessStatus](request)
+	defer func() { err = statusUpdater.CloseWithStatusUpdate(ctx, r.Client.Status(), err) }()
+
+	request.Status.ObservedGeneration = request.Generation
+
+	setState := func(state lusv1beta1.LustreFileSystemAccessState, message string, claimRef *corev1.LocalObjectReference) {
+		request.Status.State = state
+		request.Status.Message = message
+		request.Status.PersistentVolumeClaimRef = claimRef
+	}
+
+	ref := request.Spec.FileSystemRef
+	fs := &lusv1beta1.LustreFileSystem{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, fs); err != nil {
+		if errors.IsNotFound(err) {
+			// An approval is for the file system that existed, so a file system created later with the same name
+			// evaluates the request again
+			message := fmt.Sprintf("file system '%s/%s' does not exist", ref.Namespace, ref.Name)
+			meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+				Type:               lusv1beta1.ConditionApproved,
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: request.Generation,
+				Reason:             lusv1beta1.ConditionReasonFileSystemNotFound,
+				Message:            message,
+			})
+
+			setState(lusv1beta1.AccessRequestFileSystemNotFound, message, nil)
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, err
+	}
+
+	ns := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: request.Namespace}, ns); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	r.evaluateApproval(request, fs, ns)
+
+	approval := meta.FindStatusCondition(request.Status.Conditions, lusv1beta1.ConditionApproved)
+	switch {
+	case approval == nil:
+		setState(lusv1beta1.AccessRequestPending, "waiting for an administrator to approve the request", nil)
+		return ctrl.Result{}, nil
+	case approval.Status != metav1.ConditionTrue:
+		setState(lusv1beta1.AccessRequestDenied, approval.Message, nil)
+		return ctrl.Result{}, nil
+	}
+
+	// A grant that names its claim takes precedence over the request, so the LustreFileSystem controller won't grant it
+	spec, err := r.getNamespaceGrant(fs, ns)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(spec.ClaimName) != 0 && !slices.Contains(spec.Modes, request.Spec.Mode) {
+		setState(lusv1beta1.AccessRequestConflict, fmt.Sprintf("file system '%s/%s' already grants the namespace access with claim '%s', which can't hold a claim for mode '%s'", fs.Namespace, fs.Name, spec.ClaimName, request.Spec.Mode), nil)
+		return ctrl.Result{}, nil
+	}
+
+	// The LustreFileSystem controller grants the access of an approved request; report how far it got
+	access, found := fs.Status.Namespaces[request.Namespace].Modes[request.Spec.Mode]
+	switch {
+	case !found:
+		setState(lusv1beta1.AccessRequestApproved, "waiting for the namespace access to be granted", nil)
+	case access.State == lusv1beta1.NamespaceAccessReady:
+		setState(lusv1beta1.AccessRequestReady, "", access.PersistentVolumeClaimRef)
+	default:
+		setState(lusv1beta1.AccessRequestApproved, fmt.Sprintf("namespace access is %s: %s", access.State, access.Message), nil)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// evaluateApproval sets the Approved condition of the request from the access policy of the file system. A request
+// the policy doesn't allow is denied, even when an administrator approved it. An allowed request is approved when
+// the policy approves requests automatically; otherwise the condition is left for an administrator to set.
+func (r *LustreFileSystemAccessReconciler) evaluateApproval(request *lusv1beta1.LustreFileSystemAccess, fs *lusv1beta1.LustreFileSystem, ns *corev1.Namespace) {
+	approval := meta.FindStatusCondition(request.Status.Conditions, lusv1beta1.ConditionApproved)
+
+	if err := fs.AccessAllowed(ns, request.Spec.Mode); err != nil {
+		if approval == nil || approval.Reason != lusv1beta1.ConditionReasonNotAllowed || approval.Message != err.Error() {
+			r.Recorder.Event(request, corev1.EventTypeWarning, eventReasonAccessNotAllowed, err.Error())
+		}
+
+		meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+			Type:               lusv1beta1.ConditionApproved,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: request.Generation,
+			Reason:             lusv1beta1.ConditionReasonNotAllowed,
+			Message:            err.Error(),
+		})
+
+		return
+	}
+
+	// The request was allowed again, or its file system exists again, so it's evaluated like a new request
+	if approval != nil && (approval.Reason == lusv1beta1.ConditionReasonNotAllowed || approval.Reason == lusv1beta1.ConditionReasonFileSystemNotFound) {
+		meta.RemoveStatusCondition(&request.Status.Conditions, lusv1beta1.ConditionApproved)
+		approval = nil
+	}
+
+	if approval == nil && fs.Spec.AccessPolicy.Approval == lusv1beta1.AccessApprovalAutomatic {
+		message := fmt.Sprintf("approved by the access policy of file system '%s/%s'", fs.Namespace, fs.Name)
+		r.Recorder.Event(request, corev1.EventTypeNormal, eventReasonAccessApproved, message)
+
+		meta.SetStatusCondition(&request.Status.Conditions, metav1.Condition{
+			Type:               lusv1beta1.ConditionApproved,
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: request.Generation,
+			Reason:             lusv1beta1.ConditionReasonAutoApproved,
+			Message:            message,
+		})
+	}
+}
+
+// getNamespaceGrant returns the namespace access the file system grants the namespace in its specification or with
+// its namespace selector, the same way the LustreFileSystem controller finds it. It's empty when there's no grant.
+func (r *LustreFileSystemAccessReconciler) getNamespaceGrant(fs *lusv1beta1.LustreFileSystem, ns *corev1.Namespace) (lusv1beta1.LustreFileSystemNamespaceSpec, error) {
+	if spec, found := fs.Spec.Namespaces[ns.Name]; found {
+		return spec, nil
+	}
+
+	if fs.Spec.NamespaceSelector == nil || r.IgnoredNamespaces.Ignored(ns) {
+		return lusv1beta1.LustreFileSystemNamespaceSpec{}, nil
+	}
+
+	selector, err := fs.Spec.NamespaceSelector.AsSelector()
+	if err != nil {
+		return lusv1beta1.LustreFileSystemNamespaceSpec{}, err
+	}
+
+	if !selector.Matches(labels.Set(ns.Labels)) {
+		return lusv1beta1.LustreFileSystemNamespaceSpec{}, nil
+	}
+
+	return fs.Spec.NamespaceSelector.NamespaceSpec(), nil
+}
+
+// getFileSystemHandler maps a LustreFileSystem to the access requests that reference it
+func (r *LustreFileSystemAccessReconciler) getFileSystemHandler(ctx context.Context, o client.Object) []reconcile.Request {
+	requests := &lusv1beta1.LustreFileSystemAccessList{}
+	if err := r.List(ctx, requests, client.MatchingFields{fileSystemRefIndexField: o.GetNamespace() + "/" + o.GetName()}); err != nil {
+		return nil
+	}
+
+	return accessRequests(requests)
+}
+
+// getNamespaceHandler maps a namespace to the access requests in it, since a change to its labels may change
+// whether the access policy allows them
+func (r *LustreFileSystemAccessReconciler) getNamespaceHandler(ctx context.Context, o client.Object) []reconcile.Request {
+	requests := &lusv1beta1.LustreFileSystemAccessList{}
+	if err := r.List(ctx, requests, client.InNamespace(o.GetName())); err != nil {
+		return nil
+	}
+
+	return accessRequests(requests)
+}
+
+func accessRequests(requests *lusv1beta1.LustreFileSystemAccessList) []reconcile.Request {
+	res := make([]reconcile.Request, 0, len(requests.Items))
+	for _, request := range requests.Items {
+		res = append(res, reconcile.Request{NamespacedName: types.NamespacedName{Name: request.Name, Namespace: request.Namespace}})
+	}
+
+	return res
+}
+
+// SetupWithManager sets up the controller with the Manager. The file system reference index it uses is
+// registered by the LustreFileSystem controller, which must be set up with the same Manager.
+func (r *LustreFileSystemAccessReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&lusv1beta1.LustreFileSystemAccess{}).
+		Watches(
+			// Watch the file systems so a request follows changes to the access policy and to the namespace access
+			&lusv1beta1.LustreFileSystem{}, handler.EnqueueRequestsFromMapFunc(r.getFileSystemHandler),
+		).
+		Watches(
+			&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.getNamespaceHandler),
+			builder.WithPredicates(namespaceChangedPredicate()),
+		).
+		Complete(r)
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	lusv1beta1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1beta1"
+)
+
+const (
+	OperationCreate = "create"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+)
+
+var (
+	// PersistentVolumeOperationsTotal counts the persistent volume operations performed by the operator
+	PersistentVolumeOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lustre_fs_persistent_volume_operations_total",
+			Help: "Number of persistent volume create, update, and delete operations",
+		},
+		[]string{"operation"},
+	)
+
+	// PersistentVolumeClaimOperationsTotal counts the persistent volume claim operations performed by the operator
+	PersistentVolumeClaimOperationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lustre_fs_persistent_volume_claim_operations_total",
+			Help: "Number of persistent volume claim create, update, and delete operations",
+		},
+		[]string{"operation"},
+	)
+
+	// ReconcileErrorsTotal counts the errors returned from reconciling a LustreFileSystem, by reason
+	ReconcileErrorsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "lustre_fs_reconcile_errors_total",
+			Help: "Number of errors encountered while reconciling a LustreFileSystem, by reason",
+		},
+		[]string{"reason"},
+	)
+
+	// NamespaceAccessReadySeconds observes how long a namespace access waited before becoming ready
+	NamespaceAccessReadySeconds = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "lustre_fs_namespace_access_ready_seconds",
+			Help:    "Time for a namespace access to become ready",
+			Buckets: prometheus.ExponentialBuckets(0.5, 2, 14),
+		},
+	)
+)
+
+var (
+	fileSystemsDesc = prometheus.NewDesc(
+		"lustre_fs_filesystems",
+		"Number of LustreFileSystem resources",
+		nil, nil,
+	)
+
+	namespaceAccessStateDesc = prometheus.NewDesc(
+		"lustre_fs_namespace_access_state",
+		"State of each namespace access to a LustreFileSystem; the current state has the value 1",
+		[]string{"filesystem_namespace", "filesystem", "namespace", "mode", "state"}, nil,
+	)
+
+	namespaceQuotaUsedBytesDesc = prometheus.NewDesc(
+		"lustre_fs_namespace_quota_used_bytes",
+		"Space used by the project quota of each namespace of a LustreFileSystem when it was last read",
+		[]string{"filesystem_namespace", "filesystem", "namespace"}, nil,
+	)
+
+	namespaceQuotaUsedInodesDesc = prometheus.NewDesc(
+		"lustre_fs_namespace_quota_used_inodes",
+		"Files and directories used by the project quota of each namespace of a LustreFileSystem when it was last read",
+		[]string{"filesystem_namespace", "filesystem", "namespace"}, nil,
+	)
+
+	namespaceQuotaLimitBytesDesc = prometheus.NewDesc(
+		"lustre_fs_namespace_quota_limit_bytes",
+		"Space limit of the project quota of each namespace of a LustreFileSystem that has one",
+		[]string{"filesystem_namespace", "filesystem", "namespace"}, nil,
+	)
+
+	namespaceQuotaLimitInodesDesc = prometheus.NewDesc(
+		"lustre_fs_namespace_quota_limit_inodes",
+		"File and directory limit of the project quota of each namespace of a LustreFileSystem that has one",
+		[]string{"filesystem_namespace", "filesystem", "namespace"}, nil,
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		PersistentVolumeOperationsTotal,
+		PersistentVolumeClaimOperationsTotal,
+		ReconcileErrorsTotal,
+		NamespaceAccessReadySeconds,
+	)
+}
+
+// collector reports gauges derived from the LustreFileSystem resources at the time of the scrape
+type collector struct {
+	reader client.Reader
+}
+
+// RegisterCollector registers a collector that reads the LustreFileSystem resources from the reader
+// each time the metrics are scraped. Registering more than once is not an error.
+func RegisterCollector(reader client.Reader) error {
+	if err := metrics.Registry.Register(&collector{reader: reader}); err != nil {
+		if !errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Describe implements prometheus.Collector
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fileSystemsDesc
+	ch <- namespaceAccessStateDesc
+	ch <- namespaceQuotaUsedBytesDesc
+	ch <- namespaceQuotaUsedInodesDesc
+	ch <- namespaceQuotaLimitBytesDesc
+	ch <- namespaceQuotaLimitInodesDesc
+}
+
+// Collect implements prometheus.Collector
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	filesystems := &lusv1beta1.LustreFileSystemList{}
+	if err := c.reader.List(context.Background(), filesystems); err != nil {
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(fileSystemsDesc, prometheus.GaugeValue, float64(len(filesystems.Items)))
+
+	for _, fs := range filesystems.Items {
+		for namespace, namespaceStatus := range fs.Status.Namespaces {
+			for mode, access := range namespaceStatus.Modes {
+				for _, state := range lusv1beta1.NamespaceAccessStates() {
+					value := 0.0
+					if access.State == state {
+						value = 1.0
+					}
+
+					ch <- prometheus.MustNewConstMetric(namespaceAccessStateDesc, prometheus.GaugeValue, value,
+						fs.Namespace, fs.Name, namespace, string(mode), string(state))
+				}
+			}
+
+			if quota := namespaceStatus.Quota; quota != nil {
+				c.collectQuota(ch, &fs, namespace, quota)
+			}
+		}
+	}
+}
+
+// collectQuota reports the usage of the project quota of the namespace once it has been read, and the limits that
+// were set on it
+func (c *collector) collectQuota(ch chan<- prometheus.Metric, fs *lusv1beta1.LustreFileSystem, namespace string, quota *lusv1beta1.LustreFileSystemNamespaceQuotaStatus) {
+	if quota.BlockUsage != nil {
+		ch <- prometheus.MustNewConstMetric(namespaceQuotaUsedBytesDesc, prometheus.GaugeValue, float64(quota.BlockUsage.Value()),
+			fs.Namespace, fs.Name, namespace)
+		ch <- prometheus.MustNewConstMetric(namespaceQuotaUsedInodesDesc, prometheus.GaugeValue, float64(quota.InodeUsage),
+			fs.Namespace, fs.Name, namespace)
+	}
+
+	if quota.Limits == nil {
+		return
+	}
+
+	if quota.Limits.BlockLimit != nil {
+		ch <- prometheus.MustNewConstMetric(namespaceQuotaLimitBytesDesc, prometheus.GaugeValue, float64(quota.Limits.BlockLimit.Value()),
+			fs.Namespace, fs.Name, namespace)
+	}
+
+	if quota.Limits.InodeLimit != nil {
+		ch <- prometheus.MustNewConstMetric(namespaceQuotaLimitInodesDesc, prometheus.GaugeValue, float64(*quota.Limits.InodeLimit),
+			fs.Namespace, fs.Name, namespace)
+	}
+}
@@ -1,5 +1,5 @@
 /*
- * Copyright 2021-2024 Hewlett Packard Enterprise Development LP
+ * Copyright 2021-2026 Hewlett Packard Enterprise Development LP
  * Other additional copyright holders may be indicated within.
  *
  * The entirety of this work is licensed under the Apache License,
@@ -21,12 +21,13 @@ package controller
 
 import (
 	"context"
-	"os"
 	"path/filepath"
 	"testing"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,6 +39,7 @@ import (
 
 	lusv1alpha1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1alpha1"
 	lusv1beta1 "github.com/NearNodeFlash/lustre-fs-operator/api/v1beta1"
+	"github.com/NearNodeFlash/lustre-fs-operator/internal/lfs"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -50,6 +52,9 @@ var testEnv *envtest.Environment
 var ctx context.Context
 var cancel context.CancelFunc
 
+// fakeLfs keeps the project quotas set by the LustreFileSystem controller
+var fakeLfs = lfs.NewFakeRunner()
+
 func TestAPIs(t *testing.T) {
 	RegisterFailHandler(Fail)
 
@@ -64,8 +69,10 @@ var _ = BeforeSuite(func() {
 	By("bootstrapping test environment")
 	var err error
 
-	err = os.Setenv("LUSTRE_CSI_SERVICE_NAME", "lustre-csi.hpe.com")
-	Expect(err).NotTo(HaveOccurred())
+	lusv1beta1.SetDefaults(lusv1beta1.LustreFileSystemDefaults{
+		CSIDriver:        "lustre-csi.hpe.com",
+		StorageClassName: "nnf-lustre-fs",
+	})
 
 	// See https://github.com/kubernetes-sigs/controller-runtime/issues/1882
 	// about getting the conversion webhook to register properly.
@@ -104,6 +111,10 @@ var _ = BeforeSuite(func() {
 	Expect(err).NotTo(HaveOccurred())
 	Expect(k8sClient).NotTo(BeNil())
 
+	// The webhook validates the CSI driver against the cluster's CSIDriver objects
+	csiDriver := &storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: "lustre-csi.hpe.com"}}
+	Expect(k8sClient.Create(ctx, csiDriver)).To(Succeed())
+
 	// start webhook server using Manager
 	webhookInstallOptions := &testEnv.WebhookInstallOptions
 	k8sManager, err := ctrl.NewManager(cfg, ctrl.Options{
@@ -114,17 +125,31 @@ var _ = BeforeSuite(func() {
 			CertDir: webhookInstallOptions.LocalServingCertDir,
 		}),
 		LeaderElection: false,
+		Cache:          ManagerCacheOptions(),
+		Client:         ManagerClientOptions(),
 	})
 	Expect(err).ToNot(HaveOccurred())
 
 	err = (&lusv1beta1.LustreFileSystem{}).SetupWebhookWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
+	err = (&lusv1beta1.LustreFileSystemAccess{}).SetupWebhookWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
 	// +crdbumper:scaffold:builder
 
 	err = (&LustreFileSystemReconciler{
-		Client: k8sManager.GetClient(),
-		Scheme: k8sManager.GetScheme(),
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Recorder: k8sManager.GetEventRecorderFor("lustre-fs-operator"),
+		Quotas:   fakeLfs,
+	}).SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+
+	err = (&LustreFileSystemAccessReconciler{
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Recorder: k8sManager.GetEventRecorderFor("lustre-fs-operator"),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
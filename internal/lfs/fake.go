@@ -0,0 +1,149 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lfs
+
+import (
+	"context"
+	"sync"
+)
+
+// FakeRunner is a Runner that keeps the project quotas in memory, so the quotas can be managed without a
+// Lustre file system
+type FakeRunner struct {
+	lock     sync.Mutex
+	projects map[string]int64
+	limits   map[project]Limits
+	usages   map[project]Usage
+	err      error
+}
+
+// project identifies a project on a file system
+type project struct {
+	mountRoot string
+	id        int64
+}
+
+var _ Runner = &FakeRunner{}
+
+// NewFakeRunner returns a FakeRunner without any project
+func NewFakeRunner() *FakeRunner {
+	return &FakeRunner{
+		projects: map[string]int64{},
+		limits:   map[project]Limits{},
+		usages:   map[project]Usage{},
+	}
+}
+
+// GetProject implements Runner
+func (r *FakeRunner) GetProject(ctx context.Context, path string) (int64, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return r.projects[path], nil
+}
+
+// SetProject implements Runner
+func (r *FakeRunner) SetProject(ctx context.Context, path string, projectID int64) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	r.projects[path] = projectID
+	return nil
+}
+
+// ClearProject implements Runner
+func (r *FakeRunner) ClearProject(ctx context.Context, path string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	delete(r.projects, path)
+	return nil
+}
+
+// SetQuota implements Runner
+func (r *FakeRunner) SetQuota(ctx context.Context, mountRoot string, projectID int64, limits Limits) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+
+	r.limits[project{mountRoot: mountRoot, id: projectID}] = limits
+	return nil
+}
+
+// GetQuota implements Runner
+func (r *FakeRunner) GetQuota(ctx context.Context, mountRoot string, projectID int64) (Usage, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return Usage{}, r.err
+	}
+
+	return r.usages[project{mountRoot: mountRoot, id: projectID}], nil
+}
+
+// Project returns the project ID assigned to the directory, if any
+func (r *FakeRunner) Project(path string) (int64, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	projectID, found := r.projects[path]
+	return projectID, found
+}
+
+// Limits returns the limits of the project, if any were set
+func (r *FakeRunner) Limits(mountRoot string, projectID int64) (Limits, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	limits, found := r.limits[project{mountRoot: mountRoot, id: projectID}]
+	return limits, found
+}
+
+// SetUsage sets the usage returned for the project
+func (r *FakeRunner) SetUsage(mountRoot string, projectID int64, usage Usage) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.usages[project{mountRoot: mountRoot, id: projectID}] = usage
+}
+
+// SetError makes every command fail with the error until it's cleared with nil
+func (r *FakeRunner) SetError(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.err = err
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package lfs manages the project quotas of a Lustre file system with the lfs command
+package lfs
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limits are the hard limits of a project quota. Zero is no limit.
+type Limits struct {
+	// BlockBytes is the limit on the space used by the project, in bytes
+	BlockBytes int64
+
+	// Inodes is the limit on the number of files and directories of the project
+	Inodes int64
+}
+
+// Usage is the space and the number of files and directories used by a project
+type Usage struct {
+	// BlockBytes is the space used by the project, in bytes
+	BlockBytes int64
+
+	// Inodes is the number of files and directories of the project
+	Inodes int64
+}
+
+// Runner runs the lfs commands that manage the project quotas of a Lustre file system. The paths are in the
+// file system as mounted where the commands run.
+type Runner interface {
+	// GetProject returns the project ID of the directory, which is zero when it has none
+	GetProject(ctx context.Context, path string) (int64, error)
+
+	// SetProject assigns the project ID to the directory and everything in it, and makes the files and
+	// directories created in it inherit the project ID. It walks the whole tree, so it takes a while on a
+	// directory with many files.
+	SetProject(ctx context.Context, path string, projectID int64) error
+
+	// ClearProject removes the project ID and the inherit flag from the directory and everything in it
+	ClearProject(ctx context.Context, path string) error
+
+	// SetQuota sets the limits of the project on the file system mounted at the mount root
+	SetQuota(ctx context.Context, mountRoot string, projectID int64, limits Limits) error
+
+	// GetQuota returns the usage of the project on the file system mounted at the mount root
+	GetQuota(ctx context.Context, mountRoot string, projectID int64) (Usage, error)
+}
+
+// ExecRunner is a Runner that runs the lfs command
+type ExecRunner struct {
+	// Command is the path of the lfs command
+	Command string
+
+	// Timeout is how long a command may run before it's killed. Zero doesn't bound the commands.
+	Timeout time.Duration
+}
+
+var _ Runner = &ExecRunner{}
+
+// run runs the lfs command with the arguments and returns its output. The commands run in the reconcile worker,
+// so each one is bounded by the timeout.
+func (r *ExecRunner) run(ctx context.Context, args ...string) (string, error) {
+	if r.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, r.Command, args...)
+
+	// A child that still holds the output open mustn't keep the command from returning once it's killed
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("'%s %s' didn't finish within %s", r.Command, strings.Join(args, " "), r.Timeout)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("'%s %s' failed: %w: %s", r.Command, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+
+	return string(output), nil
+}
+
+// GetProject implements Runner
+func (r *ExecRunner) GetProject(ctx context.Context, path string) (int64, error) {
+	output, err := r.run(ctx, "project", "-d", path)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseProject(output)
+}
+
+// SetProject implements Runner
+func (r *ExecRunner) SetProject(ctx context.Context, path string, projectID int64) error {
+	_, err := r.run(ctx, "project", "-p", strconv.FormatInt(projectID, 10), "-s", "-r", path)
+	return err
+}
+
+// ClearProject implements Runner
+func (r *ExecRunner) ClearProject(ctx context.Context, path string) error {
+	_, err := r.run(ctx, "project", "-C", "-r", path)
+	return err
+}
+
+// SetQuota implements Runner. The block limit is rounded up to a whole kilobyte.
+func (r *ExecRunner) SetQuota(ctx context.Context, mountRoot string, projectID int64, limits Limits) error {
+	_, err := r.run(ctx, "setquota", "-p", strconv.FormatInt(projectID, 10),
+		"-B", strconv.FormatInt((limits.BlockBytes+1023)/1024, 10)+"k",
+		"-I", strconv.FormatInt(limits.Inodes, 10),
+		mountRoot)
+	return err
+}
+
+// GetQuota implements Runner
+func (r *ExecRunner) GetQuota(ctx context.Context, mountRoot string, projectID int64) (Usage, error) {
+	output, err := r.run(ctx, "quota", "-q", "-p", strconv.FormatInt(projectID, 10), mountRoot)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return parseQuota(output)
+}
+
+// parseProject parses the output of 'lfs project -d', which is the project ID, the inherit flag and the path of
+// the directory
+func parseProject(output string) (int64, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return 0, fmt.Errorf("unexpected project output '%s'", strings.TrimSpace(output))
+	}
+
+	projectID, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("unexpected project output '%s': %w", strings.TrimSpace(output), err)
+	}
+
+	return projectID, nil
+}
+
+// parseQuota parses the output of 'lfs quota -q', which is the file system followed by the kilobytes used, the
+// block quota, limit and grace, then the files used, the file quota, limit and grace. A usage over its quota is
+// marked with a '*'. A long file system name is printed on its own line, so the fields are read across lines.
+func parseQuota(output string) (Usage, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 9 {
+		return Usage{}, fmt.Errorf("unexpected quota output '%s'", strings.TrimSpace(output))
+	}
+
+	parse := func(field string) (int64, error) {
+		value, err := strconv.ParseInt(strings.TrimSuffix(field, "*"), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected quota output '%s': %w", strings.TrimSpace(output), err)
+		}
+
+		return value, nil
+	}
+
+	kbytes, err := parse(fields[1])
+	if err != nil {
+		return Usage{}, err
+	}
+
+	inodes, err := parse(fields[5])
+	if err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{BlockBytes: kbytes * 1024, Inodes: inodes}, nil
+}
@@ -0,0 +1,96 @@
+/*
+ * Copyright 2026 Hewlett Packard Enterprise Development LP
+ * Other additional copyright holders may be indicated within.
+ *
+ * The entirety of this work is licensed under the Apache License,
+ * Version 2.0 (the "License"); you may not use this file except
+ * in compliance with the License.
+ *
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package lfs
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("lfs", func() {
+
+	DescribeTable("parses the quota usage",
+		func(output string, usage Usage) {
+			Expect(parseQuota(output)).To(Equal(usage))
+		},
+		Entry("within its limits", "      /lus/w0       4       0  102400       -       1       0    1000       -\n",
+			Usage{BlockBytes: 4096, Inodes: 1}),
+		Entry("over its limits", "      /lus/w0  204800*      0  102400   6d23h    1001*      0    1000   6d23h\n",
+			Usage{BlockBytes: 204800 * 1024, Inodes: 1001}),
+		Entry("with the file system on its own line", "/lus/a-file-system-with-a-long-mount-root\n       8       0       0       -       2       0       0       -\n",
+			Usage{BlockBytes: 8192, Inodes: 2}),
+	)
+
+	It("parses the project ID", func() {
+		Expect(parseProject("    1000 P /lus/w0/projects/default\n")).To(Equal(int64(1000)))
+		Expect(parseProject("       0 - /lus/w0/projects/default\n")).To(Equal(int64(0)))
+
+		_, err := parseProject("lfs: failed to get xattr for '/lus/w0/missing': No such file or directory\n")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails to parse an unexpected quota output", func() {
+		_, err := parseQuota("lfs: quotactl failed: No such device\n")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("runs the lfs command", func() {
+		// A stand-in for lfs that prints its arguments, then a directory without a project or a quota usage
+		script := filepath.Join(GinkgoT().TempDir(), "lfs")
+		Expect(os.WriteFile(script, []byte("#!/bin/sh\necho \"$@\" >> \"$0.log\"\n"+
+			"case \"$1\" in\nproject) echo \"0 - $3\" ;;\n*) echo '/lus/w0 8 0 0 - 2 0 0 -' ;;\nesac\n"), 0o755)).To(Succeed())
+
+		runner := &ExecRunner{Command: script}
+		Expect(runner.GetProject(context.TODO(), "/lus/w0/projects/default")).To(Equal(int64(0)))
+		Expect(runner.SetProject(context.TODO(), "/lus/w0/projects/default", 1000)).To(Succeed())
+		Expect(runner.SetQuota(context.TODO(), "/lus/w0", 1000, Limits{BlockBytes: 1025, Inodes: 10})).To(Succeed())
+		Expect(runner.GetQuota(context.TODO(), "/lus/w0", 1000)).To(Equal(Usage{BlockBytes: 8192, Inodes: 2}))
+		Expect(runner.ClearProject(context.TODO(), "/lus/w0/projects/default")).To(Succeed())
+
+		log, err := os.ReadFile(script + ".log")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(log)).To(Equal(
+			"project -d /lus/w0/projects/default\n" +
+				"project -p 1000 -s -r /lus/w0/projects/default\n" +
+				"setquota -p 1000 -B 2k -I 10 /lus/w0\n" +
+				"quota -q -p 1000 /lus/w0\n" +
+				"project -C -r /lus/w0/projects/default\n"))
+	})
+
+	It("kills an lfs command that runs past its timeout", func() {
+		script := filepath.Join(GinkgoT().TempDir(), "lfs")
+		Expect(os.WriteFile(script, []byte("#!/bin/sh\nsleep 30\n"), 0o755)).To(Succeed())
+
+		runner := &ExecRunner{Command: script, Timeout: 100 * time.Millisecond}
+		start := time.Now()
+		Expect(runner.SetProject(context.TODO(), "/lus/w0", 1000)).To(MatchError(ContainSubstring("didn't finish within 100ms")))
+		Expect(time.Since(start)).To(BeNumerically("<", 10*time.Second))
+	})
+
+	It("reports the output of a failed lfs command", func() {
+		runner := &ExecRunner{Command: "false"}
+		Expect(runner.SetProject(context.TODO(), "/lus/w0", 1000)).To(MatchError(ContainSubstring("'false project -p 1000 -s -r /lus/w0' failed")))
+	})
+})